@@ -0,0 +1,236 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// AuditRecord의 Operation에 기록되는 작업 이름입니다.
+const (
+	AuditWrite         = "write"
+	AuditAppend        = "append"
+	AuditDeleteValue   = "delete-value"
+	AuditDeleteSection = "delete-section"
+	AuditClear         = "clear"
+)
+
+// AuditRecord 구조체는 하나의 config 변경 작업에 대한 감사 기록입니다.
+// 민감한 key의 OldValue와 NewValue는 Redacted로 기록됩니다.
+// =======================================
+//
+// {"time":"2022-10-18T09:15:00Z","pid":1234,"user":"admin","op":"write","file":"/etc/app.ini",
+// "section":"Database","key":"Password","old":"******","new":"******"}
+//
+// =======================================
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	PID       int       `json:"pid"`
+	User      string    `json:"user"`
+	Operation string    `json:"op"`
+	File      string    `json:"file"`
+	Section   string    `json:"section,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	OldValue  string    `json:"old,omitempty"`
+	NewValue  string    `json:"new,omitempty"`
+}
+
+// AuditSink 인터페이스는 감사 기록을 저장하는 대상입니다.
+type AuditSink interface {
+	Record(record AuditRecord) error
+}
+
+// auditFunc 타입은 함수를 AuditSink로 사용합니다.
+type auditFunc func(record AuditRecord) error
+
+func (f auditFunc) Record(record AuditRecord) error { return f(record) }
+
+// WriterAuditSink 함수는 감사 기록을 한 줄의 JSON으로 io.Writer에 기록하는 AuditSink를 반환합니다.
+func WriterAuditSink(w io.Writer) AuditSink {
+	mu := &sync.Mutex{}
+
+	return auditFunc(func(record AuditRecord) error {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, werr := w.Write(append(line, '\n'))
+		return werr
+	})
+}
+
+// FileAuditSink 함수는 감사 기록을 파일 끝에 추가하는 AuditSink를 반환합니다.
+// 파일은 기록할 때마다 추가 전용 모드로 열리며, 존재하지 않을 경우 0600 권한으로 생성됩니다.
+func FileAuditSink(path string) AuditSink {
+	return auditFunc(func(record AuditRecord) error {
+		fi, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+
+		if werr := WriterAuditSink(fi).Record(record); werr != nil {
+			fi.Close()
+			return werr
+		}
+		return fi.Close()
+	})
+}
+
+// SetAuditSink 함수는 Write, DeleteValue, DeleteSection, Clear 작업을 기록할 AuditSink를 지정합니다.
+// nil을 지정하면 감사 기록을 남기지 않습니다.
+func (conf *Configuration) SetAuditSink(sink AuditSink) {
+	conf.auditor = sink
+}
+
+// AuditFilter 구조체는 ReadAudit에서 감사 기록을 선택하는 조건입니다.
+// 공백값 또는 zero value인 조건은 무시됩니다.
+type AuditFilter struct {
+	Since     time.Time
+	Until     time.Time
+	User      string
+	Operation string
+	Section   string
+	Key       string
+}
+
+// Match 함수는 감사 기록이 조건에 해당하는지 확인합니다.
+func (filter AuditFilter) Match(record AuditRecord) bool {
+	switch {
+	case !filter.Since.IsZero() && record.Time.Before(filter.Since):
+		return false
+	case !filter.Until.IsZero() && record.Time.After(filter.Until):
+		return false
+	case filter.User != "" && record.User != filter.User:
+		return false
+	case filter.Operation != "" && record.Operation != filter.Operation:
+		return false
+	case filter.Section != "" && record.Section != filter.Section:
+		return false
+	case filter.Key != "" && record.Key != filter.Key:
+		return false
+	}
+	return true
+}
+
+// ReadAudit 함수는 JSON 한 줄 형식의 감사 기록을 읽어 조건에 해당하는 기록을 반환합니다.
+// 공백 줄은 무시하며, 해석할 수 없는 줄이 있을 경우 에러를 반환합니다.
+func ReadAudit(r io.Reader, filter AuditFilter) ([]AuditRecord, error) {
+	var records []AuditRecord
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.New(fmt.Sprint("ReadAudit : line ", line, ", ", err))
+		}
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(fmt.Sprint("ReadAudit : ", err))
+	}
+	return records, nil
+}
+
+// ReadAuditFile 함수는 감사 기록 파일을 읽어 조건에 해당하는 기록을 반환합니다.
+func ReadAuditFile(path string, filter AuditFilter) ([]AuditRecord, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint("ReadAuditFile : ", err))
+	}
+	defer fi.Close()
+
+	return ReadAudit(fi, filter)
+}
+
+// Replay 함수는 감사 기록의 작업을 순서대로 config 파일에 다시 적용합니다.
+// 민감한 value는 Redacted로 기록되어 있으므로, 해당 value를 기록하는 작업이 있을 경우 에러를 반환합니다.
+func (conf *Configuration) Replay(records []AuditRecord) error {
+	for i, record := range records {
+		var err error
+
+		switch record.Operation {
+		case AuditWrite:
+			if record.NewValue == Redacted {
+				return errors.New(fmt.Sprint("Replay : record ", i, ", cannot replay redacted value of ", joinSection(record.Section, record.Key)))
+			}
+			err = conf.Write(record.Section, record.Key, record.NewValue)
+		case AuditAppend:
+			if record.NewValue == Redacted {
+				return errors.New(fmt.Sprint("Replay : record ", i, ", cannot replay redacted value of ", joinSection(record.Section, record.Key)))
+			}
+			err = conf.Append(record.Section, record.Key, record.NewValue)
+		case AuditDeleteValue:
+			err = conf.DeleteValue(record.Section, record.Key)
+		case AuditDeleteSection:
+			err = conf.DeleteSection(record.Section)
+		case AuditClear:
+			err = conf.Clear()
+		default:
+			err = errors.New("unknown operation " + record.Operation)
+		}
+
+		if err != nil {
+			return errors.New(fmt.Sprint("Replay : record ", i, ", ", err))
+		}
+	}
+	return nil
+}
+
+// audit 함수는 작업을 감사 기록으로 남깁니다.
+// path는 실제로 기록된 파일이며, include 또는 conf.d 파일일 수 있습니다.
+// AuditSink가 지정되지 않았을 경우 아무것도 하지 않습니다.
+func (conf *Configuration) audit(operation, path, section, key, before, after string) error {
+	if conf.auditor == nil {
+		return nil
+	}
+
+	record := AuditRecord{
+		Time:      time.Now().UTC(),
+		PID:       os.Getpid(),
+		User:      currentUser(),
+		Operation: operation,
+		File:      path,
+		Section:   section,
+		Key:       key,
+	}
+	if before != "" {
+		record.OldValue = conf.redact(section, key, before)
+	}
+	if after != "" {
+		record.NewValue = conf.redact(section, key, after)
+	}
+
+	if err := conf.auditor.Record(record); err != nil {
+		return errors.New(fmt.Sprint("audit : cannot record ", operation, ", ", err))
+	}
+	return nil
+}
+
+func currentUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
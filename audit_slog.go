@@ -0,0 +1,37 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package conf4g
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogAuditSink 함수는 감사 기록을 slog.Handler에 INFO 레벨로 전달하는 AuditSink를 반환합니다.
+// 각 필드는 AuditRecord의 JSON 이름을 속성 이름으로 사용합니다.
+func SlogAuditSink(handler slog.Handler) AuditSink {
+	return auditFunc(func(record AuditRecord) error {
+		ctx := context.Background()
+		if !handler.Enabled(ctx, slog.LevelInfo) {
+			return nil
+		}
+
+		entry := slog.NewRecord(record.Time, slog.LevelInfo, "conf4g audit", 0)
+		entry.AddAttrs(
+			slog.Int("pid", record.PID),
+			slog.String("user", record.User),
+			slog.String("op", record.Operation),
+			slog.String("file", record.File),
+			slog.String("section", record.Section),
+			slog.String("key", record.Key),
+			slog.String("old", record.OldValue),
+			slog.String("new", record.NewValue),
+		)
+		return handler.Handle(ctx, entry)
+	})
+}
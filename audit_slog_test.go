@@ -0,0 +1,27 @@
+//go:build go1.21
+
+package conf4g
+
+import (
+	"bytes"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSlogAuditFunction(t *testing.T) {
+	Convey("Slog Audit Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[Section001]\nKey001=Value001\n")
+
+		var buffer bytes.Buffer
+		conf := makeTestConfig(path)
+		conf.SetAuditSink(SlogAuditSink(slog.NewTextHandler(&buffer, nil)))
+
+		So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+		So(buffer.String(), ShouldContainSubstring, "op=write")
+		So(buffer.String(), ShouldContainSubstring, "old=Value001 new=Value002")
+	})
+}
@@ -0,0 +1,109 @@
+package conf4g
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAuditFunction(t *testing.T) {
+
+	/*
+		variable.SetAuditSink(FileAuditSink("audit.log"))
+		variable.Write("Database", "Password", "p@ss")
+
+		--> {"time":"...","pid":1234,"user":"admin","op":"write","file":"...","section":"Database","key":"Password","new":"******"}
+	*/
+
+	Convey("Audit Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "[Section001]\nKey001=Value001\n")
+
+		conf := makeTestConfig(path)
+
+		Convey("Audit Writer", func() {
+			var buffer bytes.Buffer
+			conf.SetAuditSink(WriterAuditSink(&buffer))
+
+			So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+			So(conf.Write("Section001", "Password", "p@ss"), ShouldBeNil)
+			So(conf.DeleteValue("Section001", "Key001"), ShouldBeNil)
+			So(conf.DeleteSection("Section001"), ShouldBeNil)
+
+			records, err := ReadAudit(&buffer, AuditFilter{})
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, 4)
+
+			So(records[0].Operation, ShouldEqual, AuditWrite)
+			So(records[0].OldValue, ShouldEqual, "Value001")
+			So(records[0].NewValue, ShouldEqual, "Value002")
+			So(records[0].File, ShouldEqual, path)
+			So(records[0].User, ShouldNotBeEmpty)
+
+			So(records[1].NewValue, ShouldEqual, Redacted)
+			So(records[2].Operation, ShouldEqual, AuditDeleteValue)
+			So(records[2].OldValue, ShouldEqual, "Value002")
+			So(records[3].Operation, ShouldEqual, AuditDeleteSection)
+		})
+
+		Convey("Audit File", func() {
+			logpath := filepath.Join(dir, "audit.log")
+			conf.SetAuditSink(FileAuditSink(logpath))
+
+			conf.Write("Section001", "Key001", "Value002")
+			conf.Write("Section002", "Key001", "Value001")
+			conf.Clear()
+
+			records, err := ReadAuditFile(logpath, AuditFilter{Section: "Section002"})
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, 2)
+			So(records[1].Operation, ShouldEqual, AuditDeleteSection)
+
+			records, _ = ReadAuditFile(logpath, AuditFilter{Operation: AuditClear})
+			So(records, ShouldHaveLength, 1)
+		})
+
+		Convey("Audit Include File", func() {
+			mainpath := filepath.Join(dir, "main.ini")
+			basepath := filepath.Join(dir, "base.ini")
+			writeTestFile(basepath, "[Base]\nKey001=Value001\n")
+			writeTestFile(mainpath, "include=base.ini\n[Section001]\nKey001=Value001\n")
+
+			main := makeTestConfig(mainpath)
+			var buffer bytes.Buffer
+			main.SetAuditSink(WriterAuditSink(&buffer))
+
+			So(main.DeleteValue("Base", "Key001"), ShouldBeNil)
+			So(main.Write("Section001", "Key002", "Value002"), ShouldBeNil)
+
+			records, _ := ReadAudit(&buffer, AuditFilter{})
+			So(records, ShouldHaveLength, 2)
+			So(records[0].File, ShouldEqual, basepath)
+			So(records[1].File, ShouldEqual, mainpath)
+		})
+
+		Convey("Audit Replay", func() {
+			var buffer bytes.Buffer
+			conf.SetAuditSink(WriterAuditSink(&buffer))
+
+			conf.Write("Section001", "Key002", "Value002")
+			conf.DeleteValue("Section001", "Key001")
+
+			records, _ := ReadAudit(&buffer, AuditFilter{})
+
+			replica := filepath.Join(dir, "replica.ini")
+			writeTestFile(replica, "[Section001]\nKey001=Value001\n")
+			target := makeTestConfig(replica)
+
+			So(target.Replay(records), ShouldBeNil)
+			So(target.Find("Section001", "Key002"), ShouldEqual, "Value002")
+			So(target.Find("Section001", "Key001"), ShouldBeEmpty)
+
+			secret := []AuditRecord{{Operation: AuditWrite, Section: "Section001", Key: "Password", NewValue: Redacted}}
+			So(target.Replay(secret), ShouldNotBeNil)
+		})
+	})
+}
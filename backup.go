@@ -0,0 +1,322 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 버전 백업 파일의 이름 형식입니다.
+// =======================================
+//
+// {파일 이름}.{version}.bak[.gz]
+// {파일 이름}.{경로 hash}.{version}.bak[.gz]	(BackupPolicy.Dir이 지정된 경우)
+//
+// app.ini.20221018T091500.000000000Z.bak
+// app.ini.3f2a9c1e.20221018T091500.000000000Z.bak.gz
+//
+// version은 백업 시각(UTC)이며, 시각 순서대로 정렬됩니다.
+// 경로 hash는 원본 파일의 절대 경로에 대한 sha256 앞 8자리로,
+// 여러 폴더의 같은 이름의 파일이 하나의 백업 폴더를 공유할 때 서로의 백업을 구분합니다.
+//
+// =======================================
+const (
+	backupSuffix   = ".bak"
+	backupGzip     = ".gz"
+	backupVersion  = "20060102T150405.000000000Z"
+	backupFileMode = 0600
+)
+
+// ErrBackupNotFound 에러는 지정된 version의 백업이 존재하지 않을 때 반환됩니다.
+var ErrBackupNotFound = errors.New("backup : version not found")
+
+// BackupPolicy 구조체는 버전 백업의 저장 위치와 보존 정책입니다.
+// =======================================
+//
+// Dir		: 백업 파일을 저장할 폴더입니다. 공백일 경우 원본 파일과 같은 폴더를 사용합니다.
+// Keep		: 파일마다 보존할 최대 백업 개수입니다. 0일 경우 개수를 제한하지 않습니다.
+// MaxAge	: 백업을 보존할 최대 기간입니다. 0일 경우 기간을 제한하지 않습니다.
+// Compress	: true일 경우 백업 파일을 gzip으로 압축합니다.
+//
+// =======================================
+type BackupPolicy struct {
+	Dir      string
+	Keep     int
+	MaxAge   time.Duration
+	Compress bool
+}
+
+// Backup 구조체는 하나의 버전 백업 파일에 대한 정보입니다.
+// Source는 백업된 원본 파일의 위치입니다.
+type Backup struct {
+	Version    string
+	Path       string
+	Source     string
+	Time       time.Time
+	Compressed bool
+}
+
+// SetBackupPolicy 함수는 버전 백업 정책을 지정합니다.
+// 정책이 지정되면 파일을 저장할 때마다 기존 내용을 버전 백업으로 남깁니다.
+// Dir이 상대 경로일 경우 config 파일의 폴더를 기준으로 합니다.
+func (conf *Configuration) SetBackupPolicy(policy BackupPolicy) error {
+	if policy.Keep < 0 || policy.MaxAge < 0 {
+		return errors.New("SetBackupPolicy : invalid retention")
+	}
+	if policy.Dir != "" && conf.confpath != "" {
+		policy.Dir = resolvePath(filepath.Dir(conf.confpath), policy.Dir)
+	}
+	conf.backup = &policy
+	return nil
+}
+
+// ListBackups 함수는 config 파일과 include 파일들의 버전 백업 목록을 최신순으로 반환합니다.
+// 백업 정책이 지정되지 않았을 경우 에러를 반환합니다.
+func (conf *Configuration) ListBackups() ([]Backup, error) {
+	conf.Read()
+
+	if conf.backup == nil {
+		return nil, errors.New("ListBackups : backup policy is not set")
+	}
+
+	sources := conf.sources
+	if len(sources) == 0 {
+		sources = []string{conf.confpath}
+	}
+
+	var list []Backup
+	for _, source := range sources {
+		backups, err := conf.backups(source)
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("ListBackups : ", err))
+		}
+		list = append(list, backups...)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Time.After(list[j].Time) })
+	return list, nil
+}
+
+// DiffBackup 함수는 지정된 version의 백업과 현재 파일의 차이를 unified diff 형식으로 반환합니다.
+// 차이가 없을 경우 공백값을 반환합니다.
+// DiffText와 같이 각 내용을 UTF-8로 변환하며, 민감한 value는 Redacted로 가려집니다.
+func (conf *Configuration) DiffBackup(version string) (string, error) {
+	conf.Read()
+
+	backup, err := conf.findBackup(version)
+	if err != nil {
+		return "", fmt.Errorf("DiffBackup : %w", err)
+	}
+
+	previous, rerr := readBackup(backup)
+	if rerr != nil {
+		return "", errors.New(fmt.Sprint("DiffBackup : cannot read backup ", rerr))
+	}
+	current, _ := conf.storage().ReadFile(backup.Source)
+
+	for _, text := range []*[]byte{&previous, &current} {
+		if decoded, _, _, derr := conf.decodeText(*text); derr == nil {
+			*text = decoded
+		}
+		if *text, rerr = conf.redactText(backup.Source, *text); rerr != nil {
+			return "", errors.New(fmt.Sprint("DiffBackup : ", rerr))
+		}
+	}
+
+	return UnifiedDiff(filepath.Base(backup.Path), filepath.Base(backup.Source), previous, current), nil
+}
+
+// Rollback 함수는 지정된 version의 백업으로 원본 파일을 복원합니다.
+// 복원 전의 내용도 새로운 버전 백업으로 남기므로 Rollback을 되돌릴 수 있습니다.
+// 임시 파일에 기록한 후 교체하므로 복원은 한번에 이루어집니다.
+// 백업은 읽을 때와 같이 인코딩을 변환하여 검증하며, 원본 파일에는 백업의 내용을 그대로 기록합니다.
+func (conf *Configuration) Rollback(version string) error {
+	backup, err := conf.findBackup(version)
+	if err != nil {
+		return fmt.Errorf("Rollback : %w", err)
+	}
+
+	previous, rerr := readBackup(backup)
+	if rerr != nil {
+		return errors.New(fmt.Sprint("Rollback : cannot read backup ", rerr))
+	}
+	text, _, _, terr := conf.decodeText(previous)
+	if terr != nil {
+		return errors.New(fmt.Sprint("Rollback : invalid backup ", terr))
+	}
+	if _, derr := conf.formatOf(backup.Source).Decode(text); derr != nil {
+		return errors.New(fmt.Sprint("Rollback : invalid backup ", derr))
+	}
+
+	conf.mu.Lock()
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+	}()
+
+	if berr := conf.saveBackup(backup.Source); berr != nil {
+		return errors.New(fmt.Sprint("Rollback : ", berr))
+	}
+	if werr := conf.storage().WriteFile(backup.Source, previous, conf.mode()); werr != nil {
+		return errors.New(fmt.Sprint("Rollback : cannot restore ", werr))
+	}
+	return nil
+}
+
+// findBackup 함수는 지정된 version의 백업을 찾습니다.
+func (conf *Configuration) findBackup(version string) (Backup, error) {
+	list, err := conf.ListBackups()
+	if err != nil {
+		return Backup{}, err
+	}
+	for _, backup := range list {
+		if backup.Version == version {
+			return backup, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("%w : %s", ErrBackupNotFound, version)
+}
+
+// saveBackup 함수는 파일의 현재 내용을 버전 백업으로 저장한 후 보존 정책을 적용합니다.
+// 백업 정책이 지정되지 않았거나 파일이 존재하지 않을 경우 아무것도 하지 않습니다.
+func (conf *Configuration) saveBackup(path string) error {
+	if conf.backup == nil {
+		return nil
+	}
+
+	data, err := conf.storage().ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	dir := conf.backupDir(path)
+	if _, direrr := exists(dir); direrr != nil {
+		if merr := os.MkdirAll(dir, 0700); merr != nil {
+			return errors.New(fmt.Sprint("backup : cannot create directory ", merr))
+		}
+	}
+
+	name := conf.backupPrefix(path) + time.Now().UTC().Format(backupVersion) + backupSuffix
+	if conf.backup.Compress {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		writer.Write(data)
+		if cerr := writer.Close(); cerr != nil {
+			return errors.New(fmt.Sprint("backup : cannot compress ", cerr))
+		}
+		data = compressed.Bytes()
+		name += backupGzip
+	}
+
+	if werr := writeAtomic(filepath.Join(dir, name), data, backupFileMode); werr != nil {
+		return errors.New(fmt.Sprint("backup : cannot write ", werr))
+	}
+	return conf.pruneBackups(path)
+}
+
+// pruneBackups 함수는 보존 개수와 보존 기간을 초과한 백업을 삭제합니다.
+func (conf *Configuration) pruneBackups(path string) error {
+	backups, err := conf.backups(path)
+	if err != nil {
+		return err
+	}
+
+	for i, backup := range backups {
+		expired := conf.backup.MaxAge > 0 && time.Since(backup.Time) > conf.backup.MaxAge
+		if conf.backup.Keep > 0 && i >= conf.backup.Keep || expired {
+			if rerr := os.Remove(backup.Path); rerr != nil {
+				return errors.New(fmt.Sprint("backup : cannot remove ", rerr))
+			}
+		}
+	}
+	return nil
+}
+
+// backups 함수는 하나의 파일에 대한 버전 백업 목록을 최신순으로 반환합니다.
+func (conf *Configuration) backups(path string) ([]Backup, error) {
+	prefix := conf.backupPrefix(path)
+
+	entries, err := os.ReadDir(conf.backupDir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var list []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		compressed := strings.HasSuffix(name, backupSuffix+backupGzip)
+		version := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupGzip), backupSuffix)
+
+		stamp, perr := time.Parse(backupVersion, version)
+		if perr != nil {
+			continue
+		}
+		list = append(list, Backup{
+			Version:    version,
+			Path:       filepath.Join(conf.backupDir(path), name),
+			Source:     path,
+			Time:       stamp,
+			Compressed: compressed,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version > list[j].Version })
+	return list, nil
+}
+
+// backupDir 함수는 파일의 버전 백업을 저장할 폴더를 반환합니다.
+func (conf *Configuration) backupDir(path string) string {
+	if conf.backup == nil || conf.backup.Dir == "" {
+		return filepath.Dir(path)
+	}
+	return conf.backup.Dir
+}
+
+// backupPrefix 함수는 파일의 버전 백업 이름에서 version 앞에 붙는 부분을 반환합니다.
+// 백업 폴더가 지정된 경우 다른 폴더의 같은 이름의 파일과 구분하기 위해 경로 hash를 포함합니다.
+func (conf *Configuration) backupPrefix(path string) string {
+	if conf.backup == nil || conf.backup.Dir == "" {
+		return filepath.Base(path) + "."
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(path)))
+	return filepath.Base(path) + "." + hex.EncodeToString(sum[:])[:8] + "."
+}
+
+// readBackup 함수는 백업 파일의 내용을 반환하며, 압축된 백업은 압축을 해제합니다.
+func readBackup(backup Backup) ([]byte, error) {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil || !backup.Compressed {
+		return data, err
+	}
+
+	reader, gerr := gzip.NewReader(bytes.NewReader(data))
+	if gerr != nil {
+		return nil, gerr
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,162 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestBackupFunction(t *testing.T) {
+
+	/*
+		variable.SetBackupPolicy(BackupPolicy{Dir: "backup", Keep: 5})
+		variable.Write("Section001", "Key001", "Value002")
+
+		--> backup/app.ini.{version}.bak
+
+		variable.Rollback(version)
+
+		--> Section001 : Key001=Value001
+	*/
+
+	Convey("Backup Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "[Section001]\nKey001=Value001\n")
+
+		conf := makeTestConfig(path)
+
+		Convey("Backup Write", func() {
+			So(conf.SetBackupPolicy(BackupPolicy{Dir: "backup"}), ShouldBeNil)
+			So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+
+			list, err := conf.ListBackups()
+			So(err, ShouldBeNil)
+			So(list, ShouldHaveLength, 1)
+			So(list[0].Source, ShouldEqual, path)
+			So(filepath.Dir(list[0].Path), ShouldEqual, filepath.Join(dir, "backup"))
+
+			data, _ := os.ReadFile(list[0].Path)
+			So(string(data), ShouldEqual, "[Section001]\nKey001=Value001\n")
+		})
+
+		Convey("Backup Shared Dir", func() {
+			other := filepath.Join(dir, "other", "app.ini")
+			writeTestFile(other, "[Section001]\nKey001=Other\n")
+			shared := filepath.Join(dir, "backup")
+
+			conf.SetBackupPolicy(BackupPolicy{Dir: shared})
+			otherconf := makeTestConfig(other)
+			otherconf.SetBackupPolicy(BackupPolicy{Dir: shared})
+
+			So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+			So(otherconf.Write("Section001", "Key001", "Other002"), ShouldBeNil)
+
+			list, _ := conf.ListBackups()
+			So(list, ShouldHaveLength, 1)
+			So(list[0].Source, ShouldEqual, path)
+
+			otherlist, _ := otherconf.ListBackups()
+			So(otherlist, ShouldHaveLength, 1)
+			So(otherlist[0].Path, ShouldNotEqual, list[0].Path)
+
+			So(conf.Rollback(list[0].Version), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+			So(otherconf.Find("Section001", "Key001"), ShouldEqual, "Other002")
+		})
+
+		Convey("Backup Rollback", func() {
+			conf.SetBackupPolicy(BackupPolicy{Compress: true})
+			conf.Write("Section001", "Key001", "Value002")
+
+			list, _ := conf.ListBackups()
+			So(list, ShouldHaveLength, 1)
+			So(list[0].Compressed, ShouldBeTrue)
+
+			diff, err := conf.DiffBackup(list[0].Version)
+			So(err, ShouldBeNil)
+			So(diff, ShouldContainSubstring, "-Key001=Value001\n+Key001=Value002\n")
+
+			So(conf.Rollback(list[0].Version), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+
+			list, _ = conf.ListBackups()
+			So(list, ShouldHaveLength, 2)
+
+			err = conf.Rollback("20000101T000000.000000000Z")
+			So(errors.Is(err, ErrBackupNotFound), ShouldBeTrue)
+		})
+
+		Convey("Backup Rollback Encoding", func() {
+			jsonpath := filepath.Join(dir, "app.json")
+			content, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(`{"Section001": {"Key001": "Value001"}}`))
+			os.WriteFile(jsonpath, content, 0644)
+
+			jsonconf := makeTestConfig(jsonpath)
+			jsonconf.SetBackupPolicy(BackupPolicy{})
+			So(jsonconf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+
+			list, _ := jsonconf.ListBackups()
+			So(jsonconf.Rollback(list[0].Version), ShouldBeNil)
+			So(jsonconf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+
+			data, _ := os.ReadFile(jsonpath)
+			So(data, ShouldResemble, content)
+		})
+
+		Convey("Backup Diff Redacted", func() {
+			conf.SetBackupPolicy(BackupPolicy{})
+			conf.Write("Section001", "Password", "old-secret")
+			conf.Write("Section001", "Password", "new-secret")
+
+			list, _ := conf.ListBackups()
+			diff, err := conf.DiffBackup(list[0].Version)
+			So(err, ShouldBeNil)
+			So(diff, ShouldNotContainSubstring, "secret")
+		})
+
+		Convey("Backup Retention", func() {
+			conf.SetBackupPolicy(BackupPolicy{Keep: 2, MaxAge: time.Hour})
+
+			old := path + "." + time.Now().Add(-2*time.Hour).UTC().Format(backupVersion) + backupSuffix
+			writeTestFile(old, "[Section001]\nKey001=Old\n")
+
+			for _, value := range []string{"Value002", "Value003", "Value004"} {
+				conf.Write("Section001", "Key001", value)
+			}
+
+			list, _ := conf.ListBackups()
+			So(list, ShouldHaveLength, 2)
+			So(list[0].Time.After(list[1].Time), ShouldBeTrue)
+
+			_, err := os.Stat(old)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Backup Disabled", func() {
+			_, err := conf.ListBackups()
+			So(err, ShouldNotBeNil)
+
+			conf.Write("Section001", "Key001", "Value002")
+
+			matches, _ := filepath.Glob(path + ".*" + backupSuffix)
+			So(matches, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestUnifiedDiffFunction(t *testing.T) {
+	Convey("UnifiedDiff Function", t, func() {
+		a := []byte("[Section001]\nKey001=Value001\nKey002=Value002\n")
+		b := []byte("[Section001]\nKey001=Value003\nKey002=Value002\nKey003=Value003\n")
+
+		So(UnifiedDiff("a.ini", "b.ini", a, a), ShouldBeEmpty)
+		So(UnifiedDiff("a.ini", "b.ini", a, b), ShouldEqual, "--- a.ini\n+++ b.ini\n@@ -1,3 +1,4 @@\n [Section001]\n-Key001=Value001\n+Key001=Value003\n Key002=Value002\n+Key003=Value003\n")
+		So(UnifiedDiff("a.ini", "b.ini", nil, []byte("Key001=Value001\n")), ShouldEqual, "--- a.ini\n+++ b.ini\n@@ -0,0 +1,1 @@\n+Key001=Value001\n")
+	})
+}
@@ -0,0 +1,575 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// conf4g 명령어는 conf4g 라이브러리를 사용하여 설정 파일을 조회/수정합니다.
+//
+// Usage:
+//
+//	conf4g [-json] [-format name] [-key-file path | -key-env name] <command> <file> [arguments]
+//
+// 종료 코드는 다음과 같습니다.
+//
+//	0 : 성공
+//	1 : 실행 중 에러
+//	2 : 잘못된 사용법
+//	3 : section 또는 key를 찾을 수 없음
+//	4 : 설정 파일이 유효하지 않거나 정렬되지 않음 (validate, fmt -check, convert -strict)
+//	5 : 두 설정 파일이 다름 (diff)
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"feature/conf4g"
+)
+
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitInvalid  = 4
+	exitDiffer   = 5
+)
+
+const usage = `usage: conf4g [-json] [-format name] [-key-file path | -key-env name] <command> <file> [arguments]
+
+commands:
+  get            <file> <section> <key>          print a value
+  set            <file> <section> <key> <value>  write a value
+  delete         <file> <section> <key>          delete a value
+  delete-section <file> <section>                delete a section
+  list-sections  <file>                          list all sections
+  list-keys      <file> <section>                list all keys of a section
+  dump           [-reveal] <file>                print all sections and values; sensitive
+                                                 values are masked unless -reveal is given
+  validate       <file>                          check that the file can be parsed
+  fmt            [-check] <file>                 rewrite the file in canonical form
+  convert        [-strict] <file> <target>       convert to another format; target is a file
+                                                 path, or a format name to print to stdout
+  encrypt        <file> <section> <key>          encrypt a value in place
+  decrypt        <file> <section> <key>          decrypt a value in place
+  diff           [-unified] <file> <other>       compare two files; exits with 5 when they differ
+
+use an empty section ("") for the global section of .env and .properties files.
+secret values are decrypted with the key from -key-file, -key-env or $CONF4G_KEY.
+`
+
+// defaultKeyEnv 는 키가 지정되지 않았을 때 사용하는 환경변수입니다.
+const defaultKeyEnv = "CONF4G_KEY"
+
+// fileMode 는 fmt와 convert가 새로 생성하는 파일의 권한입니다.
+// 파일은 임시 파일에 기록한 후 교체하며, 기존 파일의 권한은 유지됩니다.
+const fileMode os.FileMode = 0600
+
+// command 구조체는 하위 명령어의 인자 개수와 실행 함수를 정의합니다.
+// option은 하위 명령어가 허용하는 boolean 옵션의 이름입니다.
+type command struct {
+	args   int
+	option string
+	run    func(cli *cli, conf *conf4g.Configuration, args []string) int
+}
+
+var commands = map[string]command{
+	"get":            {3, "", runGet},
+	"set":            {4, "", runSet},
+	"delete":         {3, "", runDelete},
+	"delete-section": {2, "", runDeleteSection},
+	"list-sections":  {1, "", runListSections},
+	"list-keys":      {2, "", runListKeys},
+	"dump":           {1, "-reveal", runDump},
+	"validate":       {1, "", runValidate},
+	"fmt":            {1, "-check", runFmt},
+	"convert":        {2, "-strict", runConvert},
+	"encrypt":        {3, "", runEncrypt},
+	"decrypt":        {3, "", runDecrypt},
+	"diff":           {2, "-unified", runDiff},
+}
+
+// cli 구조체는 출력 대상과 공통 옵션을 보관합니다.
+// option은 하위 명령어의 boolean 옵션이 지정되었는지 여부입니다.
+type cli struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	json    bool
+	format  string
+	keyfile string
+	keyenv  string
+	option  bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 함수는 명령어를 실행하고 종료 코드를 반환합니다.
+func run(args []string, stdout, stderr io.Writer) int {
+	c := &cli{stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("conf4g", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&c.json, "json", false, "print machine-readable JSON output")
+	flags.StringVar(&c.format, "format", "", "file format (ini, json, yaml, toml, env, properties)")
+	flags.StringVar(&c.keyfile, "key-file", "", "file containing the secret key")
+	flags.StringVar(&c.keyenv, "key-env", "", "environment variable containing the secret key")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	args = flags.Args()
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		return c.fail(exitUsage, errors.New(fmt.Sprint("unknown command ", name)))
+	}
+	args = args[1:]
+
+	if cmd.option != "" && len(args) != 0 && args[0] == cmd.option {
+		c.option = true
+		args = args[1:]
+	}
+	if len(args) != cmd.args {
+		return c.fail(exitUsage, errors.New(fmt.Sprint(name, " : expected ", cmd.args, " arguments, got ", len(args))))
+	}
+
+	if c.format != "" && formatByName(c.format) == nil {
+		return c.fail(exitUsage, errors.New(fmt.Sprint("unknown format ", c.format)))
+	}
+
+	conf, err := c.open(args[0])
+	if err != nil {
+		return c.fail(exitError, err)
+	}
+	return cmd.run(c, conf, args[1:])
+}
+
+// open 함수는 공통 옵션을 적용한 Configuration을 반환합니다.
+func (c *cli) open(file string) (*conf4g.Configuration, error) {
+	path, perr := filepath.Abs(file)
+	if perr != nil {
+		return nil, perr
+	}
+
+	conf := conf4g.MakeConfig()
+	if err := conf.Initialize(path); err != nil {
+		return nil, err
+	}
+	if c.format != "" {
+		conf.SetFormat(formatByName(c.format))
+	}
+
+	switch {
+	case c.keyfile != "":
+		conf.SetKeyProvider(conf4g.FileKeyProvider(c.keyfile))
+	case c.keyenv != "":
+		conf.SetKeyProvider(conf4g.EnvKeyProvider(c.keyenv))
+	case os.Getenv(defaultKeyEnv) != "":
+		conf.SetKeyProvider(conf4g.EnvKeyProvider(defaultKeyEnv))
+	}
+	return conf, nil
+}
+
+func runGet(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	value, err := conf.ExistValue(args[0], args[1])
+	if err != nil {
+		if errors.Is(err, conf4g.ErrSecret) {
+			return c.fail(exitError, err)
+		}
+		return c.fail(exitNotFound, err)
+	}
+
+	if c.json {
+		return c.print(map[string]string{"section": args[0], "key": args[1], "value": value})
+	}
+	fmt.Fprintln(c.stdout, value)
+	return exitOK
+}
+
+func runSet(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	if current, err := conf.ExistValue(args[0], args[1]); err == nil && current == args[2] {
+		return c.changed(false)
+	}
+	if err := conf.Write(args[0], args[1], args[2]); err != nil {
+		return c.fail(exitError, err)
+	}
+	return c.changed(true)
+}
+
+func runDelete(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	if _, err := conf.ExistValue(args[0], args[1]); err != nil {
+		return c.changed(false)
+	}
+	if err := conf.DeleteValue(args[0], args[1]); err != nil {
+		return c.fail(exitError, err)
+	}
+	return c.changed(true)
+}
+
+func runDeleteSection(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	if _, err := conf.ExistSection(args[0]); err != nil {
+		return c.changed(false)
+	}
+	if err := conf.DeleteSection(args[0]); err != nil {
+		return c.fail(exitError, err)
+	}
+	return c.changed(true)
+}
+
+func runListSections(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	sections := conf.GetSectionList()
+	sort.Strings(sections)
+
+	return c.list(sections)
+}
+
+func runListKeys(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	if _, err := conf.ExistSection(args[0]); err != nil {
+		return c.fail(exitNotFound, err)
+	}
+
+	keys := conf.GetKeyList(args[0])
+	sort.Strings(keys)
+
+	return c.list(keys)
+}
+
+func runDump(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	sections := append([]string{""}, conf.GetSectionList()...)
+	sort.Strings(sections)
+
+	snapshot := conf.RedactedSnapshot()
+	dump := map[string]map[string]string{}
+	var text bytes.Buffer
+
+	for _, section := range sections {
+		keys := conf.GetKeyList(section)
+		if keys == nil && section == "" {
+			continue
+		}
+		sort.Strings(keys)
+
+		values := map[string]string{}
+		if section != "" {
+			fmt.Fprintf(&text, "[%s]\n", section)
+		}
+		for _, key := range keys {
+			values[key] = snapshot[section][key]
+			if c.option {
+				values[key] = conf.Find(section, key)
+			}
+			fmt.Fprintf(&text, "%s=%s\n", key, values[key])
+		}
+		dump[section] = values
+	}
+
+	if c.json {
+		return c.print(dump)
+	}
+	io.Copy(c.stdout, &text)
+	return exitOK
+}
+
+func runValidate(c *cli, conf *conf4g.Configuration, args []string) int {
+	err := conf.Status()
+	if err == nil {
+		err = conf.Read()
+	}
+
+	if c.json {
+		result := map[string]interface{}{"valid": err == nil}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		c.print(result)
+	} else if err != nil {
+		fmt.Fprintln(c.stderr, "conf4g:", err)
+	}
+
+	if err != nil {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+func runFmt(c *cli, conf *conf4g.Configuration, args []string) int {
+	path, _ := conf.GetCurrentPath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c.fail(exitError, err)
+	}
+
+	text, enc, newline, terr := conf.DecodeText(data)
+	if terr != nil {
+		return c.fail(exitInvalid, terr)
+	}
+
+	doc, derr := conf.GetFormat().Decode(text)
+	if derr != nil {
+		return c.fail(exitInvalid, derr)
+	}
+
+	encoded, eerr := conf.GetFormat().Encode(doc)
+	if eerr != nil {
+		return c.fail(exitError, eerr)
+	}
+
+	// 원래 파일의 인코딩, BOM, 줄바꿈 방식을 유지합니다.
+	formatted, ferr := conf4g.EncodeText(encoded, enc, newline)
+	if ferr != nil {
+		return c.fail(exitError, ferr)
+	}
+
+	changed := !bytes.Equal(data, formatted)
+	if c.option {
+		if c.json {
+			c.print(map[string]bool{"formatted": !changed})
+		}
+		if changed {
+			return exitInvalid
+		}
+		return exitOK
+	}
+
+	if changed {
+		if werr := (conf4g.OSStorage{}).WriteFile(path, formatted, fileMode); werr != nil {
+			return c.fail(exitError, werr)
+		}
+	}
+	return c.changed(changed)
+}
+
+func runConvert(c *cli, conf *conf4g.Configuration, args []string) int {
+	path, _ := conf.GetCurrentPath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c.fail(exitError, err)
+	}
+
+	// 변환 결과는 ConvertFile과 같이 UTF-8로 기록합니다.
+	text, _, _, terr := conf.DecodeText(data)
+	if terr != nil {
+		return c.fail(exitInvalid, terr)
+	}
+
+	target := formatByName(args[0])
+	if target == nil {
+		target = conf4g.FormatFor(args[0])
+	}
+
+	converted, losses, cerr := conf4g.Convert(text, conf.GetFormat(), target)
+	if cerr != nil {
+		return c.fail(exitInvalid, cerr)
+	}
+
+	reasons := make([]string, len(losses))
+	for i, loss := range losses {
+		reasons[i] = loss.String()
+	}
+
+	if c.option && len(losses) != 0 {
+		if c.json {
+			c.print(map[string]interface{}{"converted": false, "losses": reasons})
+		} else {
+			for _, reason := range reasons {
+				fmt.Fprintln(c.stderr, "conf4g: loss:", reason)
+			}
+		}
+		return exitInvalid
+	}
+
+	for _, reason := range reasons {
+		if !c.json {
+			fmt.Fprintln(c.stderr, "conf4g: warning:", reason)
+		}
+	}
+
+	if formatByName(args[0]) != nil {
+		c.stdout.Write(converted)
+		return exitOK
+	}
+
+	if werr := (conf4g.OSStorage{}).WriteFile(args[0], converted, fileMode); werr != nil {
+		return c.fail(exitError, werr)
+	}
+	if c.json {
+		return c.print(map[string]interface{}{"converted": true, "losses": reasons})
+	}
+	return exitOK
+}
+
+func runEncrypt(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	value, err := conf.ExistValue(args[0], args[1])
+	if err != nil {
+		if errors.Is(err, conf4g.ErrSecret) {
+			return c.fail(exitError, err)
+		}
+		return c.fail(exitNotFound, err)
+	}
+	if conf.IsSecret(args[0], args[1]) {
+		return c.changed(false)
+	}
+	if werr := conf.WriteSecret(args[0], args[1], value); werr != nil {
+		return c.fail(exitError, werr)
+	}
+	return c.changed(true)
+}
+
+func runDecrypt(c *cli, conf *conf4g.Configuration, args []string) int {
+	if err := conf.Read(); err != nil {
+		return c.fail(exitError, err)
+	}
+
+	value, err := conf.ExistValue(args[0], args[1])
+	if err != nil {
+		if errors.Is(err, conf4g.ErrSecret) {
+			return c.fail(exitError, err)
+		}
+		return c.fail(exitNotFound, err)
+	}
+	if !conf.IsSecret(args[0], args[1]) {
+		return c.changed(false)
+	}
+	if werr := conf.Write(args[0], args[1], value); werr != nil {
+		return c.fail(exitError, werr)
+	}
+	return c.changed(true)
+}
+
+// formatByName 함수는 이름에 해당하는 Format을 반환합니다.
+func formatByName(name string) conf4g.Format {
+	for _, format := range []conf4g.Format{conf4g.INI, conf4g.JSON, conf4g.YAML, conf4g.TOML, conf4g.Env, conf4g.Properties} {
+		if strings.EqualFold(format.Name(), name) {
+			return format
+		}
+	}
+	return nil
+}
+
+func runDiff(c *cli, conf *conf4g.Configuration, args []string) int {
+	other, err := c.open(args[0])
+	if err != nil {
+		return c.fail(exitError, err)
+	}
+	for _, target := range []*conf4g.Configuration{conf, other} {
+		if rerr := target.Read(); rerr != nil {
+			return c.fail(exitError, rerr)
+		}
+	}
+
+	changes := conf4g.Diff(conf, other)
+
+	switch {
+	case c.option:
+		text, derr := conf4g.DiffText(conf, other)
+		if derr != nil {
+			return c.fail(exitError, derr)
+		}
+		fmt.Fprint(c.stdout, text)
+	case c.json:
+		c.print(changes)
+	case len(changes) != 0:
+		fmt.Fprint(c.stdout, changes.Table())
+	}
+
+	if len(changes) != 0 {
+		return exitDiffer
+	}
+	return exitOK
+}
+
+func (c *cli) changed(changed bool) int {
+	if c.json {
+		return c.print(map[string]bool{"changed": changed})
+	}
+	return exitOK
+}
+
+func (c *cli) list(items []string) int {
+	if c.json {
+		if items == nil {
+			items = []string{}
+		}
+		return c.print(items)
+	}
+	for _, item := range items {
+		fmt.Fprintln(c.stdout, item)
+	}
+	return exitOK
+}
+
+func (c *cli) print(value interface{}) int {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		fmt.Fprintln(c.stderr, "conf4g:", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// fail 함수는 에러를 stderr에 출력하고 종료 코드를 반환합니다.
+func (c *cli) fail(code int, err error) int {
+	if c.json {
+		enc := json.NewEncoder(c.stderr)
+		enc.SetEscapeHTML(false)
+		enc.Encode(map[string]interface{}{"error": err.Error(), "code": code})
+	} else {
+		fmt.Fprintln(c.stderr, "conf4g:", err)
+		if code == exitUsage {
+			fmt.Fprint(c.stderr, usage)
+		}
+	}
+	return code
+}
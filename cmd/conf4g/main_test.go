@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// execute 함수는 명령어를 실행하고 종료 코드와 출력을 반환합니다.
+func execute(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunFunction(t *testing.T) {
+
+	/*
+		conf4g set app.ini Section001 Key001 Value001
+		conf4g get app.ini Section001 Key001
+
+		--> Value001
+	*/
+
+	Convey("Run Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		os.WriteFile(path, []byte("[Section001]\nKey001=Value001\n"), 0644)
+
+		Convey("Run Get", func() {
+			code, stdout, _ := execute("get", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "Value001\n")
+
+			code, stdout, _ = execute("-json", "get", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"key":"Key001","section":"Section001","value":"Value001"}`+"\n")
+		})
+
+		Convey("Run Get Not Found", func() {
+			code, _, stderr := execute("-json", "get", path, "Section001", "Key002")
+			So(code, ShouldEqual, exitNotFound)
+			So(stderr, ShouldContainSubstring, `"code":3`)
+		})
+
+		Convey("Run Set", func() {
+			code, stdout, _ := execute("-json", "set", path, "Section001", "Key002", "Value002")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"changed":true}`+"\n")
+
+			code, stdout, _ = execute("-json", "set", path, "Section001", "Key002", "Value002")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"changed":false}`+"\n")
+		})
+
+		Convey("Run Delete", func() {
+			code, stdout, _ := execute("-json", "delete", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"changed":true}`+"\n")
+
+			code, stdout, _ = execute("-json", "delete-section", path, "Section002")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"changed":false}`+"\n")
+		})
+
+		Convey("Run List", func() {
+			execute("set", path, "Section002", "Key001", "Value001")
+
+			code, stdout, _ := execute("list-sections", path)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "Section001\nSection002\n")
+
+			code, stdout, _ = execute("-json", "list-keys", path, "Section001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `["Key001"]`+"\n")
+
+			code, _, _ = execute("list-keys", path, "Section003")
+			So(code, ShouldEqual, exitNotFound)
+		})
+
+		Convey("Run Dump", func() {
+			code, stdout, _ := execute("-json", "dump", path)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"Section001":{"Key001":"Value001"}}`+"\n")
+
+			execute("set", path, "Section001", "Password", "p@ss")
+
+			code, stdout, _ = execute("dump", path)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "[Section001]\nKey001=Value001\nPassword=******\n")
+
+			code, stdout, _ = execute("dump", "-reveal", path)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldContainSubstring, "Password=p@ss\n")
+		})
+
+		Convey("Run Validate", func() {
+			code, _, _ := execute("validate", path)
+			So(code, ShouldEqual, exitOK)
+
+			broken := filepath.Join(filepath.Dir(path), "broken.json")
+			os.WriteFile(broken, []byte("{"), 0644)
+			code, stdout, _ := execute("-json", "validate", broken)
+			So(code, ShouldEqual, exitInvalid)
+			So(stdout, ShouldContainSubstring, `"valid":false`)
+		})
+
+		Convey("Run Fmt", func() {
+			os.WriteFile(path, []byte("[Section001]\nKey001 = Value001\n"), 0644)
+
+			code, _, _ := execute("fmt", "-check", path)
+			So(code, ShouldEqual, exitInvalid)
+
+			code, _, _ = execute("fmt", path)
+			So(code, ShouldEqual, exitOK)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "[Section001]\nKey001=Value001\n")
+
+			info, _ := os.Stat(path)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0644))
+		})
+
+		Convey("Run Fmt Encoding", func() {
+			os.WriteFile(path, []byte("[Section001]\r\nKey001 = Value001\r\n"), 0644)
+
+			code, _, _ := execute("fmt", path)
+			So(code, ShouldEqual, exitOK)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "[Section001]\r\nKey001=Value001\r\n")
+
+			wide, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("[Section001]\r\nKey001=Value001\r\n"))
+			os.WriteFile(path, wide, 0644)
+
+			code, _, _ = execute("fmt", "-check", path)
+			So(code, ShouldEqual, exitOK)
+
+			code, stdout, _ := execute("convert", path, "json")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "{\n  \"Section001\": {\n    \"Key001\": \"Value001\"\n  }\n}\n")
+		})
+
+		Convey("Run Convert", func() {
+			code, stdout, _ := execute("convert", path, "json")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "{\n  \"Section001\": {\n    \"Key001\": \"Value001\"\n  }\n}\n")
+
+			target := filepath.Join(filepath.Dir(path), "app.env")
+			code, _, stderr := execute("convert", "-strict", path, target)
+			So(code, ShouldEqual, exitInvalid)
+			So(stderr, ShouldContainSubstring, "section merged into key SECTION001_KEY001")
+
+			code, stdout, _ = execute("-json", "convert", path, target)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldContainSubstring, `"converted":true`)
+
+			info, _ := os.Stat(target)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+		})
+
+		Convey("Run Encrypt", func() {
+			keyfile := filepath.Join(filepath.Dir(path), "secret.key")
+			os.WriteFile(keyfile, []byte("passphrase"), 0600)
+
+			code, stdout, _ := execute("-json", "-key-file", keyfile, "encrypt", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, `{"changed":true}`+"\n")
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "Key001=enc:v1:")
+
+			code, stdout, _ = execute("-key-file", keyfile, "get", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldEqual, "Value001\n")
+
+			code, _, _ = execute("get", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitError)
+
+			code, _, _ = execute("-key-file", keyfile, "decrypt", path, "Section001", "Key001")
+			So(code, ShouldEqual, exitOK)
+
+			data, _ = os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "Key001=Value001")
+		})
+
+		Convey("Run Diff", func() {
+			other := filepath.Join(filepath.Dir(path), "other.ini")
+			os.WriteFile(other, []byte("[Section001]\nKey001=Value002\nPassword=p@ss\n"), 0644)
+
+			code, stdout, _ := execute("diff", path, path)
+			So(code, ShouldEqual, exitOK)
+			So(stdout, ShouldBeEmpty)
+
+			code, stdout, _ = execute("-json", "diff", path, other)
+			So(code, ShouldEqual, exitDiffer)
+			So(stdout, ShouldEqual, `[{"section":"Section001","key":"Key001","kind":"changed","old":"Value001","new":"Value002"},{"section":"Section001","key":"Password","kind":"added","new":"******"}]`+"\n")
+
+			code, stdout, _ = execute("diff", path, other)
+			So(code, ShouldEqual, exitDiffer)
+			So(stdout, ShouldContainSubstring, "Section001  Key001    changed  Value001  Value002")
+
+			code, stdout, _ = execute("diff", "-unified", path, other)
+			So(code, ShouldEqual, exitDiffer)
+			So(stdout, ShouldContainSubstring, "-Key001=Value001\n+Key001=Value002\n")
+			So(stdout, ShouldContainSubstring, "+Password=******\n")
+		})
+
+		Convey("Run Usage", func() {
+			code, _, _ := execute()
+			So(code, ShouldEqual, exitUsage)
+
+			code, _, _ = execute("get", path)
+			So(code, ShouldEqual, exitUsage)
+
+			code, _, _ = execute("unknown", path)
+			So(code, ShouldEqual, exitUsage)
+		})
+	})
+}
@@ -13,22 +13,53 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"strings"
 	"sync"
-
-	"github.com/alyu/configparser"
 )
 
 type section struct {
-	name string
-	data map[string]string
+	name      string
+	data      map[string]string
+	origin    map[string]string
+	sensitive map[string]bool
+	files     []string
+	parent    string
+	inherited map[string]string
+	profiled  map[string]string
+	values    map[string][]string
+	order     []string
 }
 
 type Configuration struct {
 	confpath string
+	override string
+	format   Format
+	keys     KeyProvider
+	patterns []string
+	marked   map[string]bool
+	schema   map[string]bool
+	backup   *BackupPolicy
+	auditor  AuditSink
 	sections map[string]section
-
-	mu *sync.Mutex
+	order    []string
+	sources  []string
+
+	filemode  os.FileMode
+	dirmode   os.FileMode
+	store     Storage
+	envprefix string
+	liststyle *ListStyle
+	encoding  *Encoding
+	match     NameMatch
+	profile   string
+	strict    bool
+	logger    Logger
+	warned    map[string]bool
+
+	watchers []watcher
+	watchid  int
+	observed map[string]section
+
+	mu sync.Mutex
 }
 
 // MakeConfig 함수는 Configuration 구조체의 생성자 함수입니다.
@@ -37,14 +68,71 @@ type Configuration struct {
 // =======================================
 //
 // confpath	string
-// sections	map[string]section{name string, data map[string]{string}}
-// mu		*sync.Mutex
+// override	string
+// format	Format
+// keys		KeyProvider
+// patterns	[]string
+// marked	map[string]bool
+// schema	map[string]bool
+// backup	*BackupPolicy
+// auditor	AuditSink
+// sections	map[string]section{name string, data map[string]{string}, origin map[string]{string}, sensitive map[string]{bool}, files []string, parent string, inherited map[string]{string}, profiled map[string]{string}, values map[string]{[]string}, order []string}
+// order	[]string
+// sources	[]string
+// filemode	os.FileMode
+// dirmode	os.FileMode
+// store	Storage
+// envprefix	string
+// liststyle	*ListStyle
+// encoding	*Encoding
+// match	NameMatch
+// profile	string
+// strict	bool
+// logger	Logger
+// warned	map[string]bool
+// watchers	[]watcher
+// watchid	int
+// observed	map[string]section
+// mu		sync.Mutex
 //
 // confpath			: configuration 파일의 위치입니다.
+// override			: Write가 항상 기록할 override 파일의 위치입니다. 지정하지 않을 수 있습니다.
+// format			: configuration 파일의 형식입니다. 지정하지 않을 경우 확장자에 따라 선택됩니다.
+// keys			: secret value(enc:v1:...)의 복호화에 사용할 키입니다. 지정하지 않을 수 있습니다.
+// patterns			: 출력 시 value를 가릴 key의 이름 패턴입니다. 지정하지 않을 경우 DefaultSensitivePatterns를 사용합니다.
+// marked			: MarkSensitive로 지정된 민감한 key 목록입니다.
+// schema			: MarkSensitiveSchema로 지정된 민감한 key 목록입니다. 이름은 소문자로 저장됩니다.
+// backup			: 버전 백업의 저장 위치와 보존 정책입니다. 지정하지 않을 경우 버전 백업을 남기지 않습니다.
+// auditor			: 변경 작업의 감사 기록을 저장하는 대상입니다. 지정하지 않을 수 있습니다.
 // sections 		: configuration 파일의 구조를 저장하는 변수입니다.
 //          		  각 section은 name과 data로 구성되어져 있습니다.
 // sections - name	: section의 이름입니다. section마다 하나만 존재할 수 있습니다.
 // sections - data	: section의 내용입니다. 여러개의 [key=value]로 구성되어져 있습니다.
+// sections - origin	: 각 key의 value가 기록된 파일의 위치입니다.
+// sections - sensitive	: 주석에 @sensitive 태그가 지정된 key 목록입니다.
+// sections - files	: section을 포함하고 있는 파일들의 위치입니다.
+// sections - parent	: section이 상속하는 section의 이름입니다.
+// sections - inherited	: 상속된 key와 해당 value가 정의된 section의 이름입니다.
+// sections - profiled	: profile section에서 적용된 key와 해당 profile section의 이름입니다. (예: database@prod)
+// sections - values	: 반복된 key의 모든 value입니다. data에는 마지막 value가 저장됩니다.
+// sections - order	: key가 처음 병합된 순서입니다.
+// order			: section이 처음 병합된 순서입니다.
+// sources			: include와 conf.d를 포함하여 병합된 파일들의 위치입니다.
+// filemode			: 신규로 생성하는 파일의 권한입니다. 지정하지 않을 경우 0600을 사용합니다.
+// dirmode			: 신규로 생성하는 폴더의 권한입니다. 지정하지 않을 경우 0700을 사용합니다.
+// store			: config 파일을 읽고 쓰는 Storage입니다. 지정하지 않을 경우 로컬 파일 시스템을 사용합니다.
+// envprefix			: value를 덮어쓸 환경변수의 접두어입니다. 지정하지 않을 수 있습니다.
+// liststyle			: value를 목록으로 나누는 방식입니다. 지정하지 않을 경우 DefaultListStyle을 사용합니다.
+// encoding			: BOM이 없는 config 파일의 인코딩입니다. 지정하지 않을 경우 UTF8을 사용합니다.
+// match			: section과 key 이름의 비교 방식입니다. 지정하지 않을 경우 MatchExact를 사용합니다.
+// profile			: 기본 profile의 이름입니다. {envprefix}_PROFILE 환경변수(envprefix가 없을 경우 APP_PROFILE)가 우선합니다.
+// strict			: 엄격 모드의 사용 여부입니다.
+// logger			: 경고와 진단 메시지를 출력할 Logger입니다. 지정하지 않을 수 있습니다.
+// warned			: 이미 출력한 경고 메시지 목록입니다.
+// watchers			: View의 OnChange로 등록된 변경 구독 목록입니다.
+// watchid			: 마지막으로 등록된 변경 구독의 번호입니다.
+// observed			: 변경 구독에 마지막으로 알린 section 목록입니다.
+// mu				: 읽기/쓰기의 동기 처리에 사용하는 mutex입니다. zero value로 사용할 수 있습니다.
 //
 // =======================================
 func MakeConfig() *Configuration { return &Configuration{} }
@@ -56,37 +144,19 @@ func MakeConfig() *Configuration { return &Configuration{} }
 // dirPath		: {current-work-path}/config
 // filename		: {os.Args[0] -> split[0] + .ini}
 //
+// path를 지정할 경우 실행 파일의 폴더를 기준으로 하며, 절대 경로는 그대로 사용합니다.
+//
 // =======================================
 func (conf *Configuration) Initialize(path ...interface{}) error {
 	conf.sections = make(map[string]section)
 
-	target, _ := filepath.Abs(filepath.Dir(os.Args[0]))
-
-	conf.mu = &sync.Mutex{}
-
-	if strings.Contains(target, "go-build") {
-		target, _ = os.Getwd()
-	}
-
 	if path == nil {
-
-		base := filepath.Base(os.Args[0])
-
-		if strings.Contains(base, ".") {
-			bound := strings.Split(base, ".")
-			base = bound[0] + ".ini"
-		}
-
-		conf.confpath = filepath.Join(target, "config/", base)
-	} else {
-		if reflect.TypeOf(path[0]).Kind() == reflect.String {
-			conf.confpath = filepath.Join(target, filepath.Clean(fmt.Sprint(path...)))
-		} else {
-			return errors.New("Initialize : invalid parameter")
-		}
+		return conf.configure()
 	}
-
-	return nil
+	if reflect.TypeOf(path[0]).Kind() != reflect.String {
+		return errors.New("Initialize : invalid parameter")
+	}
+	return conf.configure(WithPath(fmt.Sprint(path...)))
 }
 
 // GetCurrentPath 함수는 config 파일의 경로를 반환합니다.
@@ -100,23 +170,25 @@ func (conf *Configuration) GetCurrentPath() (string, error) {
 
 // Read 함수는 config 파일의 내용을 변수에 갱신합니다.
 // 파일 경로가 정의되지 않았을 경우 에러를 반환하며 refresh 내부 함수를 호출합니다.
+// include 파일이 순환 참조되거나 필수 include 파일이 없을 경우 에러를 반환합니다.
 func (conf *Configuration) Read() error {
 	if conf.confpath == "" {
 		return errors.New("Read : missing configuration path")
 	}
-	conf.refresh()
-	return nil
+	return conf.refresh()
 }
 
 // Write 함수는 config 파일에 내용을 추가 및 갱신합니다.
+// .env, .properties 등 global 영역을 허용하는 Format은 공백 section에 기록할 수 있습니다.
+// override 파일이 지정된 경우 override 파일에, 기존 value는 해당 value가 기록된 파일에 작성합니다.
 // 작성 중 mutex의 Lock 함수를 사용하여 동기 처리를 합니다.
 // 인자값 중 하나라도 값이 없을 시 에러를 반환합니다.
 // 폴더와 파일을 경로에 위치하지 않을 경우, 해당 폴더와 파일을 신규로 생성합니다.
-// config 내용의 기록은 다음의 라이브러리를 사용합니다.
-//
-// https://github.com/alyu/configparser
+// key가 반복되어 있을 경우 하나의 value로 교체합니다. 반복된 key를 추가하려면 Append를 사용합니다.
+// 앞뒤 공백, 줄바꿈 등을 포함한 value는 Format의 규칙에 따라 따옴표와 이스케이프로 기록되므로 Find로 같은 value를 얻을 수 있습니다.
+// config 내용의 기록은 파일 확장자 또는 SetFormat으로 지정된 Format을 사용합니다.
 // =======================================
-// config 내용은 다음과 같게 작성됩니다.
+// INI Format의 config 내용은 다음과 같게 작성됩니다.
 //
 // [section]
 // key=value
@@ -137,7 +209,18 @@ func (conf *Configuration) Write(section, key, value string) (err error) {
 		conf.Read()
 	}()
 
-	if section == "" {
+	return conf.write(section, key, value)
+}
+
+// write 함수는 Write의 내부 함수로, mutex를 사용하지 않고 config 파일에 value를 기록합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) write(section, key, value string) error {
+	if conf.confpath == "" {
+		return errors.New("Write : missing configuration path")
+	}
+	section, key = conf.resolve(section, key)
+
+	if section == "" && !conf.allowGlobal(conf.target(section, key)) {
 		return errors.New("Write : missing section")
 	}
 	if key == "" {
@@ -147,47 +230,37 @@ func (conf *Configuration) Write(section, key, value string) (err error) {
 		return errors.New("Write : missing value")
 	}
 
-	if ftype, fileerr := exists(conf.confpath); fileerr != nil {
-		if _, direrr := exists(filepath.Dir(conf.confpath)); direrr != nil {
-			os.MkdirAll(filepath.Dir(conf.confpath), os.ModePerm)
+	path := conf.target(section, key)
+
+	if ftype, fileerr := conf.stat(path); fileerr != nil {
+		if _, direrr := conf.stat(filepath.Dir(path)); direrr != nil {
+			conf.storage().MkdirAll(filepath.Dir(path), conf.dirMode())
 		}
 
-		fi, ferr := os.Create(conf.confpath)
-		if ferr != nil {
+		if ferr := conf.storage().WriteFile(path, nil, conf.mode()); ferr != nil {
 			return errors.New(fmt.Sprint("Write : cannot create configuration ", ferr))
 		}
-		fi.Close()
 	} else {
 		if ftype == 0 {
 			return errors.New("Write : target is directory")
 		}
 	}
 
-	con, cerr := configparser.Read(conf.confpath)
-	if cerr != nil {
-		return errors.New("Write : cannot read configuration")
+	doc, derr := conf.readDocument(path)
+	if derr != nil {
+		return errors.New(fmt.Sprint("Write : cannot read configuration ", derr))
 	}
 
-	sec, serr := con.Section(section)
-	if serr != nil {
-		sec = con.NewSection(section)
-	}
+	doc.AddSection(conf.docSection(section, key)).Set(key, value)
 
-	if !sec.Exists(key) {
-		sec.Add(key, value)
-	} else {
-		sec.SetValueFor(key, value)
+	if serr := conf.saveDocument(doc, path); serr != nil {
+		return serr
 	}
-
-	// 2008 32bit 백업 에러, 추후 원인 분석
-	os.Remove(conf.confpath + ".bak")
-
-	err = configparser.Save(con, conf.confpath)
-
-	return nil
+	return conf.audit(AuditWrite, path, section, key, conf.sections[section].data[key], value)
 }
 
 // DeleteSection 함수는 config 파일에서 section을 삭제합니다.
+// include 및 conf.d 파일을 포함하여 section이 기록된 모든 파일에서 삭제합니다.
 // section이 지정되지 않을 시 에러를 반환합니다.
 func (conf *Configuration) DeleteSection(section string) error {
 	conf.Read()
@@ -198,28 +271,32 @@ func (conf *Configuration) DeleteSection(section string) error {
 		conf.Read()
 	}()
 
+	if conf.confpath == "" {
+		return errors.New("DeleteSection : missing configuration path")
+	}
 	if section == "" {
 		return errors.New("DeleteSection : missing section")
 	}
+	section = conf.sectionName(section)
 
-	con, cerr := configparser.Read(conf.confpath)
-
-	if cerr != nil {
-		return errors.New(fmt.Sprint("DeleteSection : cannot read configuration", cerr))
-	} else {
-		if _, derr := con.Delete(section); derr != nil {
-			return errors.New(fmt.Sprint("DeleteSection : cannot delete section", derr))
+	for _, path := range conf.files(section) {
+		doc, derr := conf.readDocument(path)
+		if derr != nil {
+			return errors.New(fmt.Sprint("DeleteSection : cannot read configuration", derr))
 		}
-	}
 
-	if serr := configparser.Save(con, conf.confpath); serr != nil {
-		return errors.New(fmt.Sprint("DeleteSection : cannot save configuration", serr))
+		doc.DeleteSection(section)
+
+		if serr := conf.saveDocument(doc, path); serr != nil {
+			return errors.New(fmt.Sprint("DeleteSection : cannot save configuration", serr))
+		}
 	}
 
-	return nil
+	return conf.audit(AuditDeleteSection, conf.confpath, section, "", "", "")
 }
 
 // DeleteValue 함수는 config 파일에서 value를 삭제합니다.
+// value가 include 또는 conf.d 파일에 기록되어 있을 경우 해당 파일에서 삭제합니다.
 // section과 key가 지정되지 않을 시 에러를 반환합니다.
 func (conf *Configuration) DeleteValue(section string, key string) error {
 	conf.Read()
@@ -230,36 +307,54 @@ func (conf *Configuration) DeleteValue(section string, key string) error {
 		conf.Read()
 	}()
 
-	if section == "" {
+	return conf.deleteValue(section, key)
+}
+
+// deleteValue 함수는 DeleteValue의 내부 함수로, mutex를 사용하지 않고 config 파일에서 value를 삭제합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) deleteValue(section string, key string) error {
+	if conf.confpath == "" {
+		return errors.New("DeleteValue : missing configuration path")
+	}
+	section, key = conf.resolve(section, key)
+
+	if section == "" && !conf.allowGlobal(conf.source(section, key)) {
 		return errors.New("DeleteValue : missing section")
 	}
 	if key == "" {
 		return errors.New("DeleteValue : missing key")
 	}
+	if from, ok := conf.sections[section].inherited[key]; ok {
+		return errors.New(fmt.Sprint("DeleteValue : value is inherited from ", from))
+	}
+
+	path := conf.source(section, key)
 
-	con, cerr := configparser.Read(conf.confpath)
-	if cerr != nil {
-		return errors.New(fmt.Sprint("DeleteValue : cannot read configuration", cerr))
+	doc, derr := conf.readDocument(path)
+	if derr != nil {
+		return errors.New(fmt.Sprint("DeleteValue : cannot read configuration", derr))
 	}
 
-	sec, serr := con.Section(section)
-	if serr != nil {
-		return errors.New(fmt.Sprint("DeleteValue : cannot load section", cerr))
+	sec := doc.Section(conf.docSection(section, key))
+	if sec == nil {
+		return errors.New("DeleteValue : cannot load section")
 	}
 
 	sec.Delete(key)
 
-	if serr2 := configparser.Save(con, conf.confpath); serr2 != nil {
+	if serr2 := conf.saveDocument(doc, path); serr2 != nil {
 		return errors.New(fmt.Sprint("DeleteValue : cannot save configuration", serr2))
 	}
 
-	return nil
+	return conf.audit(AuditDeleteValue, path, section, key, conf.sections[section].data[key], "")
 }
 
 // ExistSection 함수는 config 파일에서 section의 존재여부를 확인합니다.
 // section이 지정되지 않을 시 에러를 반환합니다.
 func (conf *Configuration) ExistSection(section string) (*section, error) {
 	conf.Read()
+	section = conf.sectionName(section)
+
 	if targetsection, ok := conf.sections[section]; ok {
 		return &targetsection, nil
 	}
@@ -268,12 +363,14 @@ func (conf *Configuration) ExistSection(section string) (*section, error) {
 
 // ExistValue 함수는 config 파일에서 지정 된 section의 value에 대한 존재여부를 확인합니다.
 // section과 key가 지정되지 않을 시 에러를 반환합니다.
+// secret value는 복호화하여 반환하며, 복호화할 수 없을 경우 ErrSecret 에러를 반환합니다.
 func (conf *Configuration) ExistValue(section, key string) (string, error) {
 	conf.Read()
+	section, key = conf.resolve(section, key)
 
 	if targetsection, serr := conf.ExistSection(section); serr == nil {
 		if targetvalue, ok := targetsection.data[key]; ok {
-			return targetvalue, nil
+			return conf.reveal(targetvalue)
 		} else {
 			return "", errors.New(fmt.Sprint("ExistValue : cannot find value"))
 		}
@@ -283,6 +380,7 @@ func (conf *Configuration) ExistValue(section, key string) (string, error) {
 }
 
 // GetSectionList 함수는 config 파일의 모든 section을 string array로 반환합니다.
+// section 헤더가 없는 global 영역은 포함되지 않습니다.
 // section이 존재하지 않을 경우 nil을 반환합니다.
 func (conf *Configuration) GetSectionList() []string {
 	conf.Read()
@@ -293,6 +391,9 @@ func (conf *Configuration) GetSectionList() []string {
 	var sectionlist []string
 
 	for name, _ := range conf.sections {
+		if name == "" {
+			continue
+		}
 		sectionlist = append(sectionlist, name)
 	}
 
@@ -322,13 +423,19 @@ func (conf *Configuration) GetKeyList(section string) []string {
 }
 
 // Find 함수는 config 파일의 지정된 section과 key에 대한 value 값을 반환합니다.
-// value가 존재하지 않을 경우 공백값을 반환합니다.
+// secret value는 복호화하여 반환합니다.
+// value가 존재하지 않거나 복호화할 수 없을 경우 공백값을 반환합니다.
 func (conf *Configuration) Find(section, key string) string {
 	conf.Read()
+	section, key = conf.resolve(section, key)
 
 	if targetsection, sok := conf.sections[section]; sok {
 		if targetvalue, vok := targetsection.data[key]; vok {
-			return targetvalue
+			value, err := conf.reveal(targetvalue)
+			if err != nil {
+				conf.warnf("conf4g: cannot decrypt %s, %v", joinSection(section, key), err)
+			}
+			return value
 		}
 	}
 	return ""
@@ -344,37 +451,42 @@ func (conf *Configuration) Status() error {
 	if conf.confpath == "" {
 		return errors.New("Status : config cannot read")
 	}
-	_, fileerr := exists(conf.confpath)
+	_, fileerr := conf.stat(conf.confpath)
 	return fileerr
 }
 
 // clear 함수는 config 파일의 모든 내용을 삭제합니다.
 // 삭제 도중 치명적인 문제가 발생할 경우 에러를 반환합니다.
 func (conf *Configuration) clear() error {
-	conf.Read()
-
-	con, cerr := configparser.Read(conf.confpath)
-	if cerr != nil {
-		return errors.New(fmt.Sprint("clear : config cannot read,", cerr))
+	if conf.confpath == "" {
+		return errors.New("clear : missing configuration path")
 	}
+	conf.Read()
 
-	sec, serr := con.AllSections()
-	if serr != nil {
-		return errors.New(fmt.Sprint("clear : section cannot read,", serr))
+	doc, derr := conf.readDocument(conf.confpath)
+	if derr != nil {
+		return errors.New(fmt.Sprint("clear : config cannot read,", derr))
 	}
 
-	sec = sec[1:]
-	for _, tempsec := range sec {
-		if derr := conf.DeleteSection(tempsec.Name()); derr != nil {
+	for _, tempsec := range doc.Sections {
+		if tempsec.Name == "" {
+			continue
+		}
+		if derr := conf.DeleteSection(tempsec.Name); derr != nil {
 			return errors.New(fmt.Sprint("clear : ", derr))
 		}
 	}
-	return nil
+	return conf.audit(AuditClear, conf.confpath, "", "", "", "")
 }
 
 // refresh 함수는 config 파일 내용을 변수에 갱신합니다
 // 변수 내용 작성 중 mutex의 Lock 함수를 사용하여 동기 처리를 합니다.
+// include 파일과 conf.d 디렉토리의 파일은 load 내부 함수에서 병합합니다.
+// 변경 구독이 있을 경우 이전 내용과 비교하여 mutex의 Unlock 후에 변경 내용을 알립니다.
 func (conf *Configuration) refresh() (ret error) {
+	var changes Changes
+	var watchers []watcher
+
 	conf.mu.Lock()
 
 	defer func() {
@@ -383,31 +495,16 @@ func (conf *Configuration) refresh() (ret error) {
 			// error
 			ret = errors.New(fmt.Sprint("refresh : ", err))
 		}
+		notify(watchers, changes)
 	}()
 
-	if _, fileerr := exists(conf.confpath); fileerr != nil {
-		ret = errors.New(fmt.Sprint("refresh : ", fileerr))
-	}
-
-	conf.sections = map[string]section{}
-	con, cerr := configparser.Read(conf.confpath)
-	if cerr != nil {
-		ret = errors.New(fmt.Sprint("refresh : config cannot read,", cerr))
-	}
-
-	sec, serr := con.AllSections()
-	if serr != nil {
-		ret = errors.New(fmt.Sprint("refresh : section cannot read,", serr))
-	}
-
-	sec = sec[1:]
-	for _, tempsec := range sec {
-		conf.sections[tempsec.Name()] = section{
-			name: tempsec.Name(),
-			data: tempsec.Options(),
-		}
+	if lerr := conf.load(); lerr != nil {
+		ret = fmt.Errorf("refresh : %w", lerr)
+		return
 	}
 
+	changes = conf.observe()
+	watchers = append(watchers, conf.watchers...)
 	return
 }
 
@@ -0,0 +1,204 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Loss 구조체는 Format 변환 중 그대로 표현할 수 없어 손실되거나 변경된 정보입니다.
+// Key가 공백일 경우 section 전체에 대한 손실입니다.
+type Loss struct {
+	Section string
+	Key     string
+	Reason  string
+}
+
+func (loss Loss) String() string {
+	name := loss.Section
+	if loss.Key != "" {
+		name = joinSection(loss.Section, loss.Key)
+	}
+	if name == "" {
+		name = "(global)"
+	}
+	return name + " : " + loss.Reason
+}
+
+// Convert 함수는 from Format의 내용을 to Format으로 변환합니다.
+// 변환 결과를 다시 읽어 원본과 비교하며, 손실되거나 변경된 정보를 Loss 목록으로 반환합니다.
+// =======================================
+//
+// [server.web]		-->	{"server": {"web": {"port": "80"}}}
+// ; comment
+// port=80
+//
+// Loss : server.web.port : comments dropped
+//
+// =======================================
+func Convert(data []byte, from, to Format) ([]byte, []Loss, error) {
+	doc, err := from.Decode(data)
+	if err != nil {
+		return nil, nil, errors.New(fmt.Sprint("Convert : cannot decode ", from.Name(), ", ", err))
+	}
+
+	var losses []Loss
+	if _, flat := to.(envFormat); flat {
+		doc, losses = flattenSections(doc)
+	} else if _, ini := to.(iniFormat); ini {
+		if _, source := from.(iniFormat); !source {
+			for _, sec := range doc.Sections {
+				if strings.Contains(sec.Name, ".") {
+					losses = append(losses, Loss{Section: sec.Name, Reason: "nested table flattened into dotted section name"})
+				}
+			}
+		}
+	}
+
+	converted, eerr := to.Encode(doc)
+	if eerr != nil {
+		return nil, nil, errors.New(fmt.Sprint("Convert : cannot encode ", to.Name(), ", ", eerr))
+	}
+
+	result, derr := to.Decode(converted)
+	if derr != nil {
+		return nil, nil, errors.New(fmt.Sprint("Convert : cannot decode converted ", to.Name(), ", ", derr))
+	}
+
+	return converted, append(losses, compareDocument(doc, result)...), nil
+}
+
+// ConvertFile 함수는 src 파일을 변환하여 dst 파일에 저장합니다.
+// 각 파일의 Format은 확장자에 따라 선택됩니다.
+// BOM이 있는 src 파일은 BOM의 인코딩으로 읽으며, dst 파일은 UTF-8로 기록합니다.
+func ConvertFile(src, dst string) ([]Loss, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint("ConvertFile : cannot read ", src, ", ", err))
+	}
+	if data, _, _, err = MakeConfig().decodeText(data); err != nil {
+		return nil, errors.New(fmt.Sprint("ConvertFile : cannot read ", src, ", ", err))
+	}
+
+	converted, losses, cerr := Convert(data, FormatFor(src), FormatFor(dst))
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	if _, direrr := exists(filepath.Dir(dst)); direrr != nil {
+		os.MkdirAll(filepath.Dir(dst), defaultDirMode)
+	}
+	if werr := writeAtomic(dst, converted, defaultFileMode); werr != nil {
+		return losses, errors.New(fmt.Sprint("ConvertFile : cannot write ", dst, ", ", werr))
+	}
+	return losses, nil
+}
+
+// flattenSections 함수는 section을 지원하지 않는 Format을 위해 모든 value를 global section으로 옮깁니다.
+// key는 section 이름과 결합하여 대문자 환경변수 형태로 변환됩니다. (예: server.web / port --> SERVER_WEB_PORT)
+// 반복된 key는 repeated 표시를 유지하므로 손실이 key마다 한번만 보고됩니다.
+func flattenSections(doc *Document) (*Document, []Loss) {
+	var losses []Loss
+
+	flat := &Document{Footer: doc.Footer}
+	global := flat.AddSection("")
+
+	for _, sec := range doc.Sections {
+		if sec.Name == "" {
+			global.Comments = append(global.Comments, sec.Comments...)
+			global.Entries = append(global.Entries, sec.Entries...)
+			continue
+		}
+		if len(sec.Entries) == 0 {
+			losses = append(losses, Loss{Section: sec.Name, Reason: "empty section dropped"})
+			continue
+		}
+
+		for i, entry := range sec.Entries {
+			key := envKey(sec.Name + "_" + entry.Key)
+			comments := entry.Comments
+			if i == 0 {
+				comments = append(append([]string{}, sec.Comments...), comments...)
+			}
+			global.Entries = append(global.Entries, &Entry{Key: key, Value: entry.Value, Comments: comments, native: entry.native, repeated: entry.repeated})
+			if !entry.repeated {
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: "section merged into key " + key})
+			}
+		}
+	}
+	return flat, losses
+}
+
+// compareDocument 함수는 변환 전후의 Document를 비교하여 손실된 정보를 반환합니다.
+// DefaultSensitivePatterns에 해당하는 key의 value는 Loss에 포함하지 않습니다.
+func compareDocument(before, after *Document) []Loss {
+	var losses []Loss
+
+	for _, sec := range before.Sections {
+		target := after.Section(sec.Name)
+		if target == nil {
+			if len(sec.Entries) == 0 && sec.Name != "" {
+				losses = append(losses, Loss{Section: sec.Name, Reason: "empty section dropped"})
+			} else if len(sec.Entries) != 0 {
+				losses = append(losses, Loss{Section: sec.Name, Reason: "section dropped"})
+			}
+			continue
+		}
+		if len(commentText(sec.Comments)) != 0 && len(commentText(target.Comments)) == 0 {
+			losses = append(losses, Loss{Section: sec.Name, Reason: "comments dropped"})
+		}
+
+		for _, entry := range sec.Entries {
+			if entry.repeated {
+				continue
+			}
+			if values := sec.Values(entry.Key); len(values) > 1 && len(target.Values(entry.Key)) < len(values) {
+				reason := "repeated values dropped"
+				if converted := target.Entry(entry.Key); converted != nil && converted.Value == strings.Join(values, ", ") {
+					reason = "repeated values converted to list"
+				}
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: reason})
+				continue
+			}
+
+			converted := target.Entry(entry.Key)
+			switch {
+			case converted == nil:
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: "key dropped"})
+				continue
+			case converted.Value != entry.Value && matchSensitive(DefaultSensitivePatterns, sec.Name, entry.Key):
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: "value changed"})
+			case converted.Value != entry.Value:
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: fmt.Sprintf("value changed from %q to %q", entry.Value, converted.Value)})
+			}
+
+			if len(commentText(entry.Comments)) != 0 && len(commentText(converted.Comments)) == 0 {
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: "comments dropped"})
+			}
+			if _, typed := entry.native.(string); entry.native != nil && !typed && converted.native == nil {
+				reason := fmt.Sprintf("%T value converted to string", entry.native)
+				if _, null := entry.native.(nullNative); null {
+					reason = "null value converted to empty string"
+				}
+				losses = append(losses, Loss{Section: sec.Name, Key: entry.Key, Reason: reason})
+			}
+		}
+	}
+
+	if len(commentText(before.Footer)) != 0 && len(commentText(after.Footer)) == 0 {
+		losses = append(losses, Loss{Reason: "trailing comments dropped"})
+	}
+
+	return losses
+}
+
+func envKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_", " ", "_").Replace(name))
+}
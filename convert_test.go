@@ -0,0 +1,109 @@
+package conf4g
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConvertFunction(t *testing.T) {
+
+	/*
+		Convert(data, from, to)
+
+		configdata : app.ini
+
+		; web server
+		[server.web]
+		port=80
+
+		Convert(data, INI, YAML)
+
+		-->
+		server:
+		  web:
+		    # web server
+		    port: "80"
+	*/
+
+	Convey("Convert Function", t, func() {
+		Convey("Convert INI To YAML", func() {
+			data := []byte("[server.web]\n; web server\nport=80\n")
+
+			converted, losses, err := Convert(data, INI, YAML)
+			So(err, ShouldBeNil)
+			So(losses, ShouldBeEmpty)
+			So(string(converted), ShouldEqual, "server:\n  web:\n    # web server\n    port: \"80\"\n")
+		})
+
+		Convey("Convert INI To JSON", func() {
+			data := []byte("[Section001]\n; comment\nKey001=Value001\n")
+
+			converted, losses, err := Convert(data, INI, JSON)
+			So(err, ShouldBeNil)
+			So(string(converted), ShouldEqual, "{\n  \"Section001\": {\n    \"Key001\": \"Value001\"\n  }\n}\n")
+			So(losses, ShouldResemble, []Loss{{Section: "Section001", Key: "Key001", Reason: "comments dropped"}})
+		})
+
+		Convey("Convert Repeated Keys", func() {
+			data := []byte("[Service]\nExecStart=a\nExecStart=b\n")
+
+			converted, losses, err := Convert(data, INI, JSON)
+			So(err, ShouldBeNil)
+			So(string(converted), ShouldEqual, "{\n  \"Service\": {\n    \"ExecStart\": [\n      \"a\",\n      \"b\"\n    ]\n  }\n}\n")
+			So(losses, ShouldResemble, []Loss{{Section: "Service", Key: "ExecStart", Reason: "repeated values converted to list"}})
+
+			converted, losses, err = Convert(data, INI, INI)
+			So(err, ShouldBeNil)
+			So(string(converted), ShouldEqual, string(data))
+			So(losses, ShouldBeEmpty)
+
+			_, losses, err = Convert(data, INI, Env)
+			So(err, ShouldBeNil)
+			So(losses, ShouldResemble, []Loss{
+				{Section: "Service", Key: "ExecStart", Reason: "section merged into key SERVICE_EXECSTART"},
+				{Key: "SERVICE_EXECSTART", Reason: "repeated values dropped"},
+			})
+		})
+
+		Convey("Convert TOML To INI", func() {
+			data := []byte("[server.web]\nport = 80\n")
+
+			converted, losses, err := Convert(data, TOML, INI)
+			So(err, ShouldBeNil)
+			So(string(converted), ShouldEqual, "[server.web]\nport=80\n")
+			So(losses, ShouldHaveLength, 2)
+			So(losses[0].String(), ShouldEqual, "server.web : nested table flattened into dotted section name")
+			So(losses[1].String(), ShouldEqual, "server.web.port : int64 value converted to string")
+		})
+
+		Convey("Convert INI To Env", func() {
+			data := []byte("[database]\nhost=localhost\n")
+
+			converted, losses, err := Convert(data, INI, Env)
+			So(err, ShouldBeNil)
+			So(string(converted), ShouldEqual, "DATABASE_HOST=localhost\n")
+			So(losses, ShouldHaveLength, 1)
+		})
+
+		Convey("Convert Invalid", func() {
+			_, _, err := Convert([]byte("{"), JSON, INI)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ConvertFile", func() {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "app.ini")
+			dst := filepath.Join(dir, "app.toml")
+			writeTestFile(src, "[Section001]\nKey001=Value001\n")
+
+			_, err := ConvertFile(src, dst)
+			So(err, ShouldBeNil)
+
+			data, _ := os.ReadFile(dst)
+			So(string(data), ShouldEqual, "[Section001]\nKey001 = \"Value001\"\n")
+		})
+	})
+}
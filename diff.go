@@ -0,0 +1,179 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// Change의 Kind에 기록되는 변경 종류입니다.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change 구조체는 두 config 사이의 하나의 차이입니다.
+// Key가 공백일 경우 section 자체가 추가되거나 삭제된 것입니다.
+// 민감한 key의 OldValue와 NewValue는 Redacted로 기록됩니다.
+type Change struct {
+	Section  string `json:"section"`
+	Key      string `json:"key,omitempty"`
+	Kind     string `json:"kind"`
+	OldValue string `json:"old,omitempty"`
+	NewValue string `json:"new,omitempty"`
+}
+
+// Changes 타입은 section과 key 순서로 정렬된 Change 목록입니다.
+type Changes []Change
+
+// Diff 함수는 a를 기준으로 b와의 차이를 section과 key 순서로 반환합니다.
+// 두 config가 동일할 경우 빈 목록을 반환합니다.
+// =======================================
+//
+// a : [Server] Port=80, Host=a	b : [Server] Port=8080, Name=b
+//
+// --> Server.Host	removed	a
+// --> Server.Name	added	b
+// --> Server.Port	changed	80 -> 8080
+//
+// =======================================
+func Diff(a, b *Configuration) Changes {
+	a.Read()
+	b.Read()
+
+	return diffSections(a.sections, b.sections, a.redact, b.redact)
+}
+
+// diffSections 함수는 Diff의 내부 함수로, 두 section 목록의 차이를 반환합니다.
+// 변경 전과 변경 후의 value는 각각 oldredact와 newredact로 가립니다.
+func diffSections(a, b map[string]section, oldredact, newredact func(section, key, value string) string) Changes {
+	names := map[string]bool{}
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+
+	var sortednames []string
+	for name := range names {
+		sortednames = append(sortednames, name)
+	}
+	sort.Strings(sortednames)
+
+	changes := Changes{}
+	for _, name := range sortednames {
+		before, inbefore := a[name]
+		after, inafter := b[name]
+
+		switch {
+		case !inbefore:
+			changes = append(changes, Change{Section: name, Kind: ChangeAdded})
+		case !inafter:
+			changes = append(changes, Change{Section: name, Kind: ChangeRemoved})
+		}
+
+		keys := map[string]bool{}
+		for key := range before.data {
+			keys[key] = true
+		}
+		for key := range after.data {
+			keys[key] = true
+		}
+
+		var sortedkeys []string
+		for key := range keys {
+			sortedkeys = append(sortedkeys, key)
+		}
+		sort.Strings(sortedkeys)
+
+		for _, key := range sortedkeys {
+			old, inold := before.data[key]
+			value, innew := after.data[key]
+
+			switch {
+			case !inold:
+				changes = append(changes, Change{Section: name, Key: key, Kind: ChangeAdded, NewValue: newredact(name, key, value)})
+			case !innew:
+				changes = append(changes, Change{Section: name, Key: key, Kind: ChangeRemoved, OldValue: oldredact(name, key, old)})
+			case old != value:
+				changes = append(changes, Change{Section: name, Key: key, Kind: ChangeChanged, OldValue: oldredact(name, key, old), NewValue: newredact(name, key, value)})
+			}
+		}
+	}
+	return changes
+}
+
+// DiffText 함수는 두 config 파일의 내용을 unified diff 형식으로 비교하여 반환합니다.
+// include와 conf.d 파일은 포함하지 않으며, 각 파일의 내용은 UTF-8로 변환하여 비교합니다.
+// 민감한 value는 Diff와 같이 Redacted로 가려지며, 해당 줄만 다시 기록되므로 나머지 줄은 파일 내용 그대로 비교됩니다.
+func DiffText(a, b *Configuration) (string, error) {
+	a.Read()
+	b.Read()
+
+	before, err := a.storage().ReadFile(a.confpath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.New(fmt.Sprint("DiffText : ", err))
+	}
+	after, berr := b.storage().ReadFile(b.confpath)
+	if berr != nil && !os.IsNotExist(berr) {
+		return "", errors.New(fmt.Sprint("DiffText : ", berr))
+	}
+	if text, _, _, derr := a.decodeText(before); derr == nil {
+		before = text
+	}
+	if text, _, _, derr := b.decodeText(after); derr == nil {
+		after = text
+	}
+
+	var rerr error
+	if before, rerr = a.redactText(a.confpath, before); rerr != nil {
+		return "", errors.New(fmt.Sprint("DiffText : ", rerr))
+	}
+	if after, rerr = b.redactText(b.confpath, after); rerr != nil {
+		return "", errors.New(fmt.Sprint("DiffText : ", rerr))
+	}
+	return UnifiedDiff(a.confpath, b.confpath, before, after), nil
+}
+
+// Table 함수는 차이를 사람이 읽기 쉬운 표 형식의 문자열로 반환합니다.
+// =======================================
+//
+// SECTION  KEY   CHANGE   OLD  NEW
+// Server   Port  changed  80   8080
+//
+// =======================================
+func (changes Changes) Table() string {
+	var text bytes.Buffer
+
+	writer := tabwriter.NewWriter(&text, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "SECTION\tKEY\tCHANGE\tOLD\tNEW")
+	for _, change := range changes {
+		section := change.Section
+		if section == "" {
+			section = "(global)"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", section, change.Key, change.Kind, change.OldValue, change.NewValue)
+	}
+	writer.Flush()
+
+	return text.String()
+}
+
+// JSON 함수는 차이를 JSON 배열로 반환합니다.
+func (changes Changes) JSON() ([]byte, error) {
+	if changes == nil {
+		changes = Changes{}
+	}
+	return json.Marshal(changes)
+}
@@ -0,0 +1,72 @@
+package conf4g
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiffFunction(t *testing.T) {
+
+	/*
+		staging.ini
+		[Server]
+		Host=a
+		Port=80
+
+		production.ini
+		[Server]
+		Name=b
+		Port=8080
+		[Database]
+		Password=p@ss
+
+		Diff(staging, production)
+
+		--> Database		added
+		--> Database.Password	added	******
+		--> Server.Host		removed	a
+		--> Server.Name		added	b
+		--> Server.Port		changed	80 -> 8080
+	*/
+
+	Convey("Diff Function", t, func() {
+		dir := t.TempDir()
+		writeTestFile(filepath.Join(dir, "staging.ini"), "[Server]\nHost=a\nPort=80\n")
+		writeTestFile(filepath.Join(dir, "production.ini"), "[Server]\nName=b\nPort=8080\n[Database]\nPassword=p@ss\n")
+
+		staging := makeTestConfig(filepath.Join(dir, "staging.ini"))
+		production := makeTestConfig(filepath.Join(dir, "production.ini"))
+
+		Convey("Diff Changes", func() {
+			changes := Diff(staging, production)
+
+			So(changes, ShouldResemble, Changes{
+				{Section: "Database", Kind: ChangeAdded},
+				{Section: "Database", Key: "Password", Kind: ChangeAdded, NewValue: Redacted},
+				{Section: "Server", Key: "Host", Kind: ChangeRemoved, OldValue: "a"},
+				{Section: "Server", Key: "Name", Kind: ChangeAdded, NewValue: "b"},
+				{Section: "Server", Key: "Port", Kind: ChangeChanged, OldValue: "80", NewValue: "8080"},
+			})
+			So(Diff(staging, staging), ShouldBeEmpty)
+		})
+
+		Convey("Diff Render", func() {
+			changes := Diff(staging, production)
+
+			So(changes.Table(), ShouldContainSubstring, "Server    Port      changed  80   8080\n")
+
+			data, err := changes.JSON()
+			So(err, ShouldBeNil)
+			So(string(data), ShouldContainSubstring, `{"section":"Server","key":"Port","kind":"changed","old":"80","new":"8080"}`)
+
+			text, terr := DiffText(staging, production)
+			So(terr, ShouldBeNil)
+			So(text, ShouldContainSubstring, "-Host=a\n")
+			So(text, ShouldContainSubstring, "+[Database]\n")
+			So(text, ShouldContainSubstring, "+Password="+Redacted+"\n")
+			So(text, ShouldNotContainSubstring, "p@ss")
+		})
+	})
+}
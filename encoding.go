@@ -0,0 +1,192 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding 구조체는 config 파일의 문자 인코딩입니다.
+// 파일은 UTF-8로 변환하여 읽어들이며, 저장할 때는 원래의 인코딩과 줄바꿈 방식으로 다시 변환합니다.
+// =======================================
+//
+// 1. 파일의 BOM				--> UTF8BOM, UTF16LE, UTF16BE
+// 2. WithEncoding 또는 SetEncoding으로 지정된 인코딩
+// 3. UTF8
+//
+// 줄바꿈 방식은 파일에 CRLF가 있을 경우 CRLF, 없을 경우 LF를 사용합니다.
+// UTF-16 파일은 항상 BOM과 함께 기록합니다.
+//
+// =======================================
+type Encoding struct {
+	name  string
+	codec encoding.Encoding
+	bom   []byte
+}
+
+// 기본 제공 Encoding 입니다.
+// CP949(MS949)는 EUC-KR을 포함하므로 EUC-KR 파일도 CP949로 읽을 수 있습니다.
+var (
+	UTF8    = Encoding{name: "utf-8"}
+	UTF8BOM = Encoding{name: "utf-8-bom", bom: []byte{0xEF, 0xBB, 0xBF}}
+	UTF16LE = Encoding{name: "utf-16le", codec: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), bom: []byte{0xFF, 0xFE}}
+	UTF16BE = Encoding{name: "utf-16be", codec: unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), bom: []byte{0xFE, 0xFF}}
+	CP949   = Encoding{name: "cp949", codec: korean.EUCKR}
+	EUCKR   = Encoding{name: "euc-kr", codec: korean.EUCKR}
+)
+
+// newlineCRLF 는 Windows 형식의 줄바꿈입니다.
+const newlineCRLF = "\r\n"
+
+// Name 함수는 Encoding의 이름을 반환합니다.
+func (enc Encoding) Name() string {
+	if enc.name == "" {
+		return UTF8.name
+	}
+	return enc.name
+}
+
+func (enc Encoding) String() string { return enc.Name() }
+
+// LookupEncoding 함수는 이름에 해당하는 Encoding을 반환합니다.
+// 대소문자를 구분하지 않으며, 지원하지 않는 이름일 경우 에러를 반환합니다.
+// =======================================
+//
+// utf-8, utf8			--> UTF8
+// utf-8-bom, utf-8-sig		--> UTF8BOM
+// utf-16, utf-16le		--> UTF16LE
+// utf-16be			--> UTF16BE
+// cp949, ms949, uhc		--> CP949
+// euc-kr, euckr		--> EUCKR
+//
+// =======================================
+func LookupEncoding(name string) (Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "utf-8", "utf8":
+		return UTF8, nil
+	case "utf-8-bom", "utf-8-sig", "utf8-bom":
+		return UTF8BOM, nil
+	case "utf-16", "utf-16le", "utf16", "utf16le":
+		return UTF16LE, nil
+	case "utf-16be", "utf16be":
+		return UTF16BE, nil
+	case "cp949", "ms949", "uhc", "windows-949":
+		return CP949, nil
+	case "euc-kr", "euckr":
+		return EUCKR, nil
+	}
+	return Encoding{}, errors.New(fmt.Sprint("LookupEncoding : unknown encoding ", name))
+}
+
+// SetEncoding 함수는 BOM이 없는 config 파일의 인코딩을 지정합니다.
+// BOM이 있는 파일은 BOM의 인코딩을 우선합니다.
+func (conf *Configuration) SetEncoding(enc Encoding) {
+	conf.encoding = &enc
+}
+
+// GetEncoding 함수는 config 파일에 적용되는 인코딩을 반환합니다.
+// 파일이 존재하지 않을 경우 지정된 인코딩을, 지정되지 않았을 경우 UTF8을 반환합니다.
+func (conf *Configuration) GetEncoding() Encoding {
+	data, err := conf.storage().ReadFile(conf.confpath)
+	if err != nil {
+		return conf.declaredEncoding()
+	}
+	return conf.detectEncoding(data)
+}
+
+// DecodeText 함수는 파일 내용을 UTF-8로 변환하고, 파일의 인코딩과 줄바꿈 방식을 반환합니다.
+// 인코딩은 BOM으로 감지하며, BOM이 없을 경우 SetEncoding으로 지정된 인코딩을 사용합니다.
+// Format의 Decode 전에 사용하며, EncodeText로 원래의 인코딩과 줄바꿈 방식을 복원합니다.
+// =======================================
+//
+// text, enc, newline, _ := conf.DecodeText(data)
+// doc, _ := conf.GetFormat().Decode(text)
+// formatted, _ := conf.GetFormat().Encode(doc)
+// data, _ = conf4g.EncodeText(formatted, enc, newline)
+//
+// =======================================
+func (conf *Configuration) DecodeText(data []byte) ([]byte, Encoding, string, error) {
+	text, enc, newline, err := conf.decodeText(data)
+	if err != nil {
+		return nil, enc, newline, errors.New(fmt.Sprint("DecodeText : ", err))
+	}
+	return text, enc, newline, nil
+}
+
+// EncodeText 함수는 UTF-8 내용을 DecodeText가 반환한 인코딩과 줄바꿈 방식으로 변환합니다.
+// 인코딩으로 표현할 수 없는 문자가 있을 경우 에러를 반환합니다.
+func EncodeText(data []byte, enc Encoding, newline string) ([]byte, error) {
+	encoded, err := encodeText(data, enc, newline)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint("EncodeText : ", err))
+	}
+	return encoded, nil
+}
+
+// declaredEncoding 함수는 지정된 인코딩을 반환하며, 지정되지 않았을 경우 UTF8을 반환합니다.
+func (conf *Configuration) declaredEncoding() Encoding {
+	if conf.encoding == nil {
+		return UTF8
+	}
+	return *conf.encoding
+}
+
+// detectEncoding 함수는 BOM으로 파일의 인코딩을 감지하며, BOM이 없을 경우 지정된 인코딩을 반환합니다.
+func (conf *Configuration) detectEncoding(data []byte) Encoding {
+	for _, enc := range []Encoding{UTF8BOM, UTF16LE, UTF16BE} {
+		if bytes.HasPrefix(data, enc.bom) {
+			return enc
+		}
+	}
+	return conf.declaredEncoding()
+}
+
+// decodeText 함수는 파일 내용을 UTF-8로 변환하고, 파일의 인코딩과 줄바꿈 방식을 반환합니다.
+func (conf *Configuration) decodeText(data []byte) ([]byte, Encoding, string, error) {
+	enc := conf.detectEncoding(data)
+	data = bytes.TrimPrefix(data, enc.bom)
+
+	if enc.codec != nil {
+		decoded, err := enc.codec.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, enc, "", errors.New(fmt.Sprint("cannot decode ", enc.Name(), ", ", err))
+		}
+		data = decoded
+	}
+
+	newline := "\n"
+	if bytes.Contains(data, []byte(newlineCRLF)) {
+		newline = newlineCRLF
+	}
+	return data, enc, newline, nil
+}
+
+// encodeText 함수는 UTF-8 내용을 지정된 인코딩과 줄바꿈 방식으로 변환합니다.
+// 인코딩으로 표현할 수 없는 문자가 있을 경우 에러를 반환합니다.
+func encodeText(data []byte, enc Encoding, newline string) ([]byte, error) {
+	if newline == newlineCRLF {
+		data = bytes.ReplaceAll(bytes.ReplaceAll(data, []byte(newlineCRLF), []byte("\n")), []byte("\n"), []byte(newlineCRLF))
+	}
+
+	if enc.codec != nil {
+		encoded, err := enc.codec.NewEncoder().Bytes(data)
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("cannot encode ", enc.Name(), ", ", err))
+		}
+		data = encoded
+	}
+	if len(enc.bom) == 0 {
+		return data, nil
+	}
+	return append(append([]byte{}, enc.bom...), data...), nil
+}
@@ -0,0 +1,100 @@
+package conf4g
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestEncodingFunction(t *testing.T) {
+
+	/*
+		legacy.ini (CP949, CRLF)
+		[서버]
+		이름=웹서버
+
+		variable, _ := New(WithPath("legacy.ini"), WithEncoding(CP949))
+		variable.Find("서버", "이름")	--> 웹서버
+	*/
+
+	Convey("Encoding Function", t, func() {
+		dir := t.TempDir()
+		content := "[서버]\r\n이름=웹서버\r\n"
+
+		Convey("Encoding CP949", func() {
+			path := filepath.Join(dir, "legacy.ini")
+			data, _ := korean.EUCKR.NewEncoder().Bytes([]byte(content))
+			writeTestFile(path, string(data))
+
+			conf, _ := New(WithPath(path), WithEncoding(CP949))
+			So(conf.GetEncoding().Name(), ShouldEqual, "cp949")
+			So(conf.Find("서버", "이름"), ShouldEqual, "웹서버")
+			So(conf.Write("서버", "설명", "한글 설명"), ShouldBeNil)
+
+			data, _ = os.ReadFile(path)
+			text, _ := korean.EUCKR.NewDecoder().Bytes(data)
+			So(string(text), ShouldEqual, content+"설명=한글 설명\r\n")
+
+			So(conf.Write("서버", "emoji", "😀"), ShouldNotBeNil)
+			after, _ := os.ReadFile(path)
+			So(after, ShouldResemble, data)
+		})
+
+		Convey("Encoding UTF-16 BOM", func() {
+			path := filepath.Join(dir, "wide.ini")
+			data, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(content))
+			writeTestFile(path, string(data))
+
+			conf, _ := New(WithPath(path))
+			So(conf.GetEncoding().Name(), ShouldEqual, "utf-16le")
+			So(conf.Find("서버", "이름"), ShouldEqual, "웹서버")
+			So(conf.Write("서버", "포트", "8080"), ShouldBeNil)
+
+			data, _ = os.ReadFile(path)
+			So(bytes.HasPrefix(data, []byte{0xFF, 0xFE}), ShouldBeTrue)
+			text, _ := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Bytes(data)
+			So(string(text), ShouldEqual, content+"포트=8080\r\n")
+		})
+
+		Convey("Encoding UTF-8 BOM", func() {
+			path := filepath.Join(dir, "bom.ini")
+			writeTestFile(path, "\xEF\xBB\xBF[서버]\n이름=웹서버\n")
+
+			conf, _ := New(WithPath(path))
+			So(conf.GetSectionList(), ShouldContain, "서버")
+			So(conf.Write("서버", "포트", "8080"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "\xEF\xBB\xBF[서버]\n이름=웹서버\n포트=8080\n")
+		})
+
+		Convey("Encoding New File", func() {
+			path := filepath.Join(dir, "new.ini")
+
+			conf, _ := New(WithPath(path), WithEncoding(UTF16BE))
+			So(conf.Write("서버", "이름", "웹서버"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(bytes.HasPrefix(data, []byte{0xFE, 0xFF}), ShouldBeTrue)
+			So(conf.Find("서버", "이름"), ShouldEqual, "웹서버")
+		})
+
+		Convey("LookupEncoding", func() {
+			enc, err := LookupEncoding("MS949")
+			So(err, ShouldBeNil)
+			So(enc.Name(), ShouldEqual, CP949.Name())
+
+			enc, err = LookupEncoding("utf-8-sig")
+			So(err, ShouldBeNil)
+			So(enc.String(), ShouldEqual, "utf-8-bom")
+
+			_, err = LookupEncoding("latin-9")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
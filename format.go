@@ -0,0 +1,454 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Format 인터페이스는 설정 파일 형식의 읽기/쓰기를 담당합니다.
+// Decode는 파일 내용을 Document로, Encode는 Document를 파일 내용으로 변환합니다.
+// Extensions는 형식을 자동으로 선택할 때 사용하는 확장자 목록입니다. (예: ".ini")
+type Format interface {
+	Name() string
+	Extensions() []string
+	Decode(data []byte) (*Document, error)
+	Encode(doc *Document) ([]byte, error)
+}
+
+// GlobalFormat 인터페이스는 section 헤더가 없는 global 영역에 key를 기록할 수 있는 Format입니다.
+// AllowGlobal이 true인 Format은 Write와 DeleteValue에 공백 section을 사용할 수 있습니다.
+type GlobalFormat interface {
+	Format
+	AllowGlobal() bool
+}
+
+// RepeatedFormat 인터페이스는 한 section에 같은 key를 여러번 기록할 수 있는 Format입니다.
+// AllowRepeated가 false인 Format에서 Append는 기존 value 뒤에 목록으로 추가합니다.
+type RepeatedFormat interface {
+	Format
+	AllowRepeated() bool
+}
+
+// Document 구조체는 Format 간에 공유되는 설정 파일의 중간 표현입니다.
+// =======================================
+//
+// Sections	: 파일에 기록된 순서대로 정렬된 section 목록입니다.
+// (이름이 공백인 section은 section 헤더가 없는 global 영역입니다.)
+// Footer	: 마지막 value 이후에 위치한 주석입니다.
+// encoding	: 파일의 문자 인코딩입니다. (readDocument로 읽어들인 경우)
+// newline	: 파일의 줄바꿈 방식입니다. 공백일 경우 LF를 사용합니다.
+// fold		: section 이름의 비교 방식이며, nil일 경우 정확히 비교합니다.
+// order	: JSON, YAML, TOML, .properties Format이 읽어들인 모든 key의 경로입니다. 다시 기록할 때 원래의 순서를 유지합니다.
+//
+// 한 단계 이상 중첩된 구조는 점(.)으로 연결된 section 이름으로 표현됩니다.
+//
+// {"server": {"web": {"port": 80}}}
+// -->
+// [server.web]
+// port=80
+//
+// =======================================
+type Document struct {
+	Sections []*DocumentSection
+	Footer   []string
+
+	encoding Encoding
+	newline  string
+	fold     func(string) string
+	order    []string
+}
+
+// nullNative 타입은 Format이 읽어들인 null 값을 표현합니다.
+// value가 갱신되지 않은 경우 다시 null로 기록됩니다.
+type nullNative struct{}
+
+// DocumentSection 구조체는 Document의 section 하나를 표현합니다.
+// Comments는 section 헤더 앞에 위치한 주석입니다.
+// style은 Format이 읽어들인 section 헤더의 표현 방식(따옴표 subsection 등)을 보관합니다.
+// inherits는 section 헤더에 지정된 상속 section의 이름입니다. ([production : base])
+// fold는 key 이름의 비교 방식이며, nil일 경우 정확히 비교합니다.
+type DocumentSection struct {
+	Name     string
+	Comments []string
+	Entries  []*Entry
+
+	style    string
+	inherits string
+	fold     func(string) string
+}
+
+// Entry 구조체는 section 내의 [key=value] 하나를 표현합니다.
+// Comments는 key 앞에 위치한 주석입니다.
+// native와 style은 Format이 읽어들인 원래의 타입과 표현 방식(따옴표 등)을 보관합니다.
+// raw는 INI, .env, .properties Format이 읽어들인 value의 원래 표현이며, value가 변경되지 않은 경우 그대로 기록합니다.
+// repeated는 한 section에 같은 key가 두번 이상 기록되어 있었는지 여부입니다.
+type Entry struct {
+	Key      string
+	Value    string
+	Comments []string
+
+	native   interface{}
+	style    string
+	raw      string
+	repeated bool
+}
+
+var (
+	formatMu sync.RWMutex
+	formats  []Format
+)
+
+// 기본 제공 Format 입니다.
+var (
+	INI        Format = iniFormat{}
+	JSON       Format = jsonFormat{}
+	YAML       Format = yamlFormat{}
+	TOML       Format = tomlFormat{}
+	Env        Format = envFormat{}
+	Properties Format = propertiesFormat{}
+)
+
+func init() {
+	RegisterFormat(INI)
+	RegisterFormat(JSON)
+	RegisterFormat(YAML)
+	RegisterFormat(TOML)
+	RegisterFormat(Env)
+	RegisterFormat(Properties)
+}
+
+// RegisterFormat 함수는 확장자로 선택할 수 있는 Format을 등록합니다.
+// 같은 확장자를 가진 Format이 이미 등록된 경우, 나중에 등록된 Format이 우선합니다.
+func RegisterFormat(format Format) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+
+	formats = append([]Format{format}, formats...)
+}
+
+// FormatFor 함수는 파일 확장자에 해당하는 Format을 반환합니다.
+// ".env.local"과 같이 확장자로 시작하는 파일 이름도 해당 Format으로 선택됩니다.
+// 해당하는 Format이 없을 경우 INI Format을 반환합니다.
+func FormatFor(path string) Format {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+
+	base := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(base)
+	for _, format := range formats {
+		for _, target := range format.Extensions() {
+			if target == ext || target == base || strings.HasPrefix(base, target+".") {
+				return format
+			}
+		}
+	}
+	return INI
+}
+
+// SetFormat 함수는 config 파일의 Format을 지정합니다.
+// nil을 지정하면 파일 확장자에 따라 Format을 선택합니다.
+func (conf *Configuration) SetFormat(format Format) {
+	conf.format = format
+}
+
+// GetFormat 함수는 config 파일에 사용되는 Format을 반환합니다.
+func (conf *Configuration) GetFormat() Format {
+	return conf.formatOf(conf.confpath)
+}
+
+// formatOf 함수는 파일에 사용할 Format을 반환합니다.
+// 지정된 Format은 config 파일에만 적용되며, 그 외의 파일은 확장자에 따라 선택합니다.
+func (conf *Configuration) formatOf(path string) Format {
+	if conf.format != nil && path == conf.confpath {
+		return conf.format
+	}
+	return FormatFor(path)
+}
+
+// allowGlobal 함수는 파일의 Format이 global 영역의 기록을 허용하는지 확인합니다.
+func (conf *Configuration) allowGlobal(path string) bool {
+	format, ok := conf.formatOf(path).(GlobalFormat)
+	return ok && format.AllowGlobal()
+}
+
+// readDocument 함수는 파일을 읽어 Document로 변환합니다.
+// 파일의 내용은 UTF-8로 변환하여 해석하며, 파일의 인코딩과 줄바꿈 방식을 Document에 보관합니다.
+func (conf *Configuration) readDocument(path string) (*Document, error) {
+	data, err := conf.storage().ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	text, enc, newline, terr := conf.decodeText(data)
+	if terr != nil {
+		return nil, terr
+	}
+
+	doc, derr := conf.formatOf(path).Decode(text)
+	if derr != nil {
+		return nil, errors.New(fmt.Sprint(conf.formatOf(path).Name(), " : ", derr))
+	}
+	doc.encoding, doc.newline = enc, newline
+	doc.setFold(conf.fold())
+	return doc, nil
+}
+
+// setFold 함수는 Document와 모든 section의 이름 비교 방식을 지정합니다.
+func (doc *Document) setFold(fold func(string) string) {
+	doc.fold = fold
+	for _, sec := range doc.Sections {
+		sec.fold = fold
+	}
+}
+
+// saveDocument 함수는 Document를 읽어들인 파일의 인코딩과 줄바꿈 방식으로 파일에 저장합니다.
+// 기존 파일이 존재할 경우 .bak 파일로 백업한 후 저장합니다.
+// 백업 정책이 지정된 경우 버전 백업도 함께 남깁니다.
+// 임시 파일에 기록한 후 교체하므로, 저장 도중 파일이 일부만 기록된 상태로 남지 않습니다.
+func (conf *Configuration) saveDocument(doc *Document, path string) error {
+	data, err := conf.encodeDocument(doc, path)
+	if err != nil {
+		return err
+	}
+	return conf.storeDocument(path, data)
+}
+
+// encodeDocument 함수는 Document를 파일에 기록할 내용으로 변환합니다.
+func (conf *Configuration) encodeDocument(doc *Document, path string) ([]byte, error) {
+	text, err := conf.formatOf(path).Encode(doc)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint(conf.formatOf(path).Name(), " : ", err))
+	}
+	return encodeText(text, doc.encoding, doc.newline)
+}
+
+// emptyDocument 함수는 아직 존재하지 않는 파일에 기록할 빈 Document를 생성합니다.
+func (conf *Configuration) emptyDocument() *Document {
+	_, enc, newline, _ := conf.decodeText(nil)
+	doc := &Document{encoding: enc, newline: newline}
+	doc.setFold(conf.fold())
+	return doc
+}
+
+// storeDocument 함수는 encodeDocument로 변환한 내용을 백업 후 파일에 저장합니다.
+func (conf *Configuration) storeDocument(path string, data []byte) error {
+	if previous, rerr := conf.storage().ReadFile(path); rerr == nil {
+		perm := conf.mode()
+		if info, serr := conf.storage().Stat(path); serr == nil {
+			perm = info.Mode().Perm()
+		}
+		// 기존 .bak 파일의 권한이 유지되지 않도록, 기록 전후에 원본 파일의 권한으로 변경합니다.
+		storage, chmod := conf.storage().(chmodStorage)
+		if chmod {
+			storage.Chmod(path+".bak", perm)
+		}
+		if berr := conf.storage().WriteFile(path+".bak", previous, perm); berr != nil {
+			return berr
+		}
+		if chmod {
+			if cerr := storage.Chmod(path+".bak", perm); cerr != nil {
+				return cerr
+			}
+		}
+	}
+	if berr := conf.saveBackup(path); berr != nil {
+		return berr
+	}
+	return conf.storage().WriteFile(path, data, conf.mode())
+}
+
+// writeAtomic 함수는 같은 폴더의 임시 파일에 내용을 기록한 후 대상 파일과 교체합니다.
+// 대상 파일이 존재할 경우 기존 파일의 권한과 소유자를 유지하며, 없을 경우 perm을 사용합니다.
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	info, serr := os.Stat(path)
+	if serr == nil {
+		perm = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, werr := temp.Write(data); werr != nil {
+		temp.Close()
+		return werr
+	}
+	if serr := temp.Sync(); serr != nil {
+		temp.Close()
+		return serr
+	}
+	if cerr := temp.Close(); cerr != nil {
+		return cerr
+	}
+	if perr := os.Chmod(temp.Name(), perm); perr != nil {
+		return perr
+	}
+	if serr == nil {
+		if oerr := chownLike(temp.Name(), info); oerr != nil {
+			return oerr
+		}
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// Section 함수는 지정된 이름의 section을 반환합니다.
+// section이 존재하지 않을 경우 nil을 반환합니다.
+func (doc *Document) Section(name string) *DocumentSection {
+	for _, sec := range doc.Sections {
+		if sameName(doc.fold, sec.Name, name) {
+			return sec
+		}
+	}
+	return nil
+}
+
+// AddSection 함수는 지정된 이름의 section을 반환하며, 존재하지 않을 경우 새로 추가합니다.
+// global section은 항상 첫번째에 위치합니다.
+func (doc *Document) AddSection(name string) *DocumentSection {
+	if sec := doc.Section(name); sec != nil {
+		return sec
+	}
+
+	sec := &DocumentSection{Name: name, fold: doc.fold}
+	if name == "" {
+		doc.Sections = append([]*DocumentSection{sec}, doc.Sections...)
+	} else {
+		doc.Sections = append(doc.Sections, sec)
+	}
+	return sec
+}
+
+// DeleteSection 함수는 지정된 이름의 section을 삭제합니다.
+// section이 존재하지 않을 경우 false를 반환합니다.
+func (doc *Document) DeleteSection(name string) bool {
+	for i, sec := range doc.Sections {
+		if sameName(doc.fold, sec.Name, name) {
+			doc.Sections = append(doc.Sections[:i], doc.Sections[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Entry 함수는 지정된 key의 첫번째 Entry를 반환합니다.
+// key가 존재하지 않을 경우 nil을 반환합니다.
+func (sec *DocumentSection) Entry(key string) *Entry {
+	for _, entry := range sec.Entries {
+		if sameName(sec.fold, entry.Key, key) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Values 함수는 지정된 key의 모든 value를 기록된 순서대로 반환합니다.
+// key가 존재하지 않을 경우 nil을 반환합니다.
+func (sec *DocumentSection) Values(key string) []string {
+	var values []string
+	for _, entry := range sec.Entries {
+		if sameName(sec.fold, entry.Key, key) {
+			values = append(values, entry.Value)
+		}
+	}
+	return values
+}
+
+// Set 함수는 지정된 key의 value를 갱신하며, key가 존재하지 않을 경우 새로 추가합니다.
+// key가 반복되어 있을 경우 첫번째 Entry만 남기고 나머지는 삭제합니다.
+func (sec *DocumentSection) Set(key, value string) {
+	entry := sec.Entry(key)
+	if entry == nil {
+		sec.Entries = append(sec.Entries, &Entry{Key: key, Value: value})
+		return
+	}
+
+	entry.Value = value
+	entries := sec.Entries[:0]
+	for _, target := range sec.Entries {
+		if !sameName(sec.fold, target.Key, key) || target == entry {
+			entries = append(entries, target)
+		}
+	}
+	sec.Entries = entries
+}
+
+// Add 함수는 지정된 key의 마지막 Entry 뒤에 value를 추가합니다.
+// key가 존재하지 않을 경우 section의 끝에 추가합니다.
+func (sec *DocumentSection) Add(key, value string) {
+	position := len(sec.Entries)
+	for i, entry := range sec.Entries {
+		if sameName(sec.fold, entry.Key, key) {
+			position = i + 1
+		}
+	}
+
+	entry := &Entry{Key: key, Value: value, repeated: sec.Entry(key) != nil}
+	sec.Entries = append(sec.Entries[:position], append([]*Entry{entry}, sec.Entries[position:]...)...)
+}
+
+// Delete 함수는 지정된 key를 삭제하며, key가 반복되어 있을 경우 모두 삭제합니다.
+// key가 존재하지 않을 경우 false를 반환합니다.
+func (sec *DocumentSection) Delete(key string) bool {
+	deleted := false
+	entries := sec.Entries[:0]
+	for _, entry := range sec.Entries {
+		if sameName(sec.fold, entry.Key, key) {
+			deleted = true
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sec.Entries = entries
+	return deleted
+}
+
+// Options 함수는 section의 모든 [key=value]를 map으로 반환합니다.
+func (sec *DocumentSection) Options() map[string]string {
+	options := make(map[string]string, len(sec.Entries))
+	for _, entry := range sec.Entries {
+		options[entry.Key] = entry.Value
+	}
+	return options
+}
+
+// Native 함수는 Entry의 value를 Format이 읽어들인 원래의 타입으로 반환합니다.
+// value가 갱신된 경우 원래 타입으로 변환을 시도하며, 변환할 수 없을 경우 문자열을 반환합니다.
+func (entry *Entry) Native() interface{} {
+	switch native := entry.native.(type) {
+	case nil:
+		return entry.Value
+	case nullNative:
+		if entry.Value == "" {
+			return nil
+		}
+	case bool:
+		if value, err := strconv.ParseBool(entry.Value); err == nil {
+			return value
+		}
+	case int64:
+		if value, err := strconv.ParseInt(entry.Value, 10, 64); err == nil {
+			return value
+		}
+	case float64:
+		if value, err := strconv.ParseFloat(entry.Value, 64); err == nil {
+			return value
+		}
+	default:
+		if stringify(native) == entry.Value {
+			return native
+		}
+	}
+	return entry.Value
+}
@@ -0,0 +1,213 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// envFormat 구조체는 .env 형식을 읽고 씁니다.
+// 모든 key는 하나의 global section에 위치하며, 주석과 export 접두어는 유지됩니다.
+// =======================================
+//
+// # comment
+// export KEY=value
+// SINGLE='literal $value'
+// DOUBLE="line1\nline2"
+// MULTI="first
+// second"
+//
+// 작은따옴표 안의 value는 그대로 사용되며, 큰따옴표 안에서는 \n, \t, \", \\, \$ 를 해석합니다.
+// 따옴표가 없는 value는 공백 뒤의 # 부터 주석으로 간주합니다.
+//
+// =======================================
+type envFormat struct{}
+
+func (envFormat) Name() string { return "env" }
+
+func (envFormat) Extensions() []string { return []string{".env"} }
+
+func (envFormat) AllowGlobal() bool { return true }
+
+func (envFormat) Decode(data []byte) (*Document, error) {
+	doc := &Document{}
+	global := doc.AddSection("")
+
+	var comments []string
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			comments = append(comments, line)
+			continue
+		}
+
+		var style string
+		if strings.HasPrefix(trimmed, "export ") {
+			style = "export "
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "export "))
+		}
+
+		eq := strings.Index(trimmed, "=")
+		if eq < 1 {
+			return nil, errors.New(fmt.Sprint("line ", i+1, " : missing '='"))
+		}
+		key := strings.TrimSpace(trimmed[:eq])
+		raw := strings.TrimLeft(trimmed[eq+1:], " \t")
+
+		for quotedEnv(raw) && closingQuote(raw[1:], raw[0]) < 0 && i+1 < len(lines) {
+			i++
+			raw += "\n" + lines[i]
+		}
+
+		value, quote, ok := envValue(raw)
+		if !ok {
+			return nil, errors.New(fmt.Sprint("key ", key, " : unterminated quoted value"))
+		}
+		style += quote
+
+		if entry := global.Entry(key); entry != nil {
+			entry.Value, entry.style, entry.raw, entry.repeated = value, style, raw, true
+			entry.Comments = append(entry.Comments, comments...)
+		} else {
+			global.Entries = append(global.Entries, &Entry{Key: key, Value: value, Comments: comments, style: style, raw: raw})
+		}
+		comments = nil
+	}
+
+	doc.Footer = comments
+
+	return doc, nil
+}
+
+func (envFormat) Encode(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, sec := range doc.Sections {
+		if sec.Name != "" && len(sec.Entries) != 0 {
+			return nil, errors.New(fmt.Sprint("section ", sec.Name, " : env supports only global section"))
+		}
+		writeHashComments(&buf, sec.Comments)
+		for _, entry := range sec.Entries {
+			writeHashComments(&buf, entry.Comments)
+			if strings.HasPrefix(entry.style, "export ") {
+				buf.WriteString("export ")
+			}
+			// 변경되지 않은 value는 따옴표와 줄 끝의 주석을 포함한 원래의 표현을 그대로 기록합니다.
+			if value, _, ok := envValue(entry.raw); ok && entry.raw != "" && value == entry.Value {
+				buf.WriteString(entry.Key + "=" + entry.raw + "\n")
+				continue
+			}
+			buf.WriteString(entry.Key + "=" + quoteEnv(entry.Value, strings.TrimPrefix(entry.style, "export ")) + "\n")
+		}
+	}
+	writeHashComments(&buf, doc.Footer)
+
+	return buf.Bytes(), nil
+}
+
+// quotedEnv 함수는 '=' 이후의 표현이 따옴표로 시작하는지 확인합니다.
+func quotedEnv(raw string) bool {
+	return strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`)
+}
+
+// envValue 함수는 '=' 이후의 원래 표현에서 value와 따옴표 방식을 반환합니다.
+// 닫는 따옴표 이후 또는 따옴표가 없는 value의 공백 뒤 # 부터는 주석으로 무시합니다.
+// 따옴표가 닫히지 않은 경우 false를 반환합니다.
+func envValue(raw string) (string, string, bool) {
+	if !quotedEnv(raw) {
+		if hash := strings.Index(raw, " #"); hash >= 0 {
+			raw = raw[:hash]
+		}
+		return strings.TrimSpace(raw), "", true
+	}
+
+	end := closingQuote(raw[1:], raw[0])
+	if end < 0 {
+		return "", "", false
+	}
+	if raw[0] == '\'' {
+		return raw[1 : end+1], "'", true
+	}
+	return unescapeEnv(raw[1 : end+1]), `"`, true
+}
+
+// closingQuote 함수는 닫는 따옴표의 위치를 반환합니다.
+// 큰따옴표 안의 이스케이프된 따옴표는 무시합니다.
+func closingQuote(body string, quote byte) int {
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && quote == '"':
+			i++
+		case body[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeEnv(value string) string {
+	var buf strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			buf.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 't':
+			buf.WriteByte('\t')
+		case 'r':
+			buf.WriteByte('\r')
+		default:
+			buf.WriteByte(value[i])
+		}
+	}
+	return buf.String()
+}
+
+// quoteEnv 함수는 value를 .env 표현식으로 변환합니다.
+// 따옴표가 필요 없는 value는 그대로 기록하며, 원래의 따옴표 방식을 가능한 유지합니다.
+func quoteEnv(value, quote string) string {
+	if quote == `'` && !strings.ContainsAny(value, "'\n") {
+		return "'" + value + "'"
+	}
+	if quote == "" && value != "" && !strings.ContainsAny(value, " \t\r\n#\"'$\\`") {
+		return value
+	}
+	if quote == "" && value == "" {
+		return ""
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// writeHashComments 함수는 주석을 # 주석으로 기록합니다.
+// 다른 형식의 주석 기호는 # 으로 변환되며, 빈 줄은 유지됩니다.
+func writeHashComments(buf *bytes.Buffer, comments []string) {
+	for _, comment := range comments {
+		trimmed := strings.TrimSpace(comment)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			buf.WriteString(comment + "\n")
+		default:
+			buf.WriteString("# " + strings.TrimSpace(strings.TrimLeft(trimmed, ";!")) + "\n")
+		}
+	}
+}
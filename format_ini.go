@@ -0,0 +1,263 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// iniFormat 구조체는 INI 형식을 읽고 씁니다.
+// 파일 형식은 https://github.com/alyu/configparser 와 호환됩니다.
+// =======================================
+//
+// ; comment
+// # comment
+// global=value
+//
+// [section]
+// key=value
+// key: value
+// keyonly
+//
+// [section.child]
+// [section "child"]	(git 형식의 subsection, [section.child]와 같습니다.)
+// [child : section]	(section의 모든 key를 상속합니다. ':' 앞뒤의 공백이 필요합니다.)
+//
+// [unit]
+// key=a		(반복된 key는 모두 보존되며, FindAll로 조회합니다.)
+// key=b
+//
+// [value]
+// quoted="  a\tb ; c\n"	(큰따옴표 안에서는 \n, \t, \r, \", \\ 를 해석합니다.)
+// joined=first \		(줄 끝의 '\' 는 공백 하나로 다음 줄과 이어집니다.)
+// second		--> "first second"
+//
+// key 줄보다 깊게 들여쓴 다음 줄들은 줄바꿈으로 이어집니다. (lines=first, "  second" --> "first\nsecond")
+//
+// ; 와 # 은 줄의 처음에 있을 경우에만 주석이므로 value 안에서는 그대로 사용할 수 있습니다.
+// 앞뒤 공백, 줄바꿈 등 그대로 기록할 수 없는 value는 큰따옴표로 감싸 기록하며,
+// 읽어들인 value가 변경되지 않은 경우 원래의 표현을 유지합니다.
+//
+// =======================================
+type iniFormat struct{}
+
+// styleQuoted 는 git 형식의 따옴표 subsection으로 읽어들인 section 헤더의 style입니다.
+const styleQuoted = "quoted"
+
+func (iniFormat) Name() string { return "ini" }
+
+func (iniFormat) Extensions() []string { return []string{".ini", ".conf", ".cfg"} }
+
+func (iniFormat) AllowRepeated() bool { return true }
+
+func (iniFormat) Decode(data []byte) (*Document, error) {
+	doc := &Document{}
+	active := doc.AddSection("")
+
+	var comments []string
+
+	// last는 다음 줄과 이어질 수 있는 마지막 entry이며, indent는 해당 key 줄의 들여쓰기입니다.
+	var last *Entry
+	var indent int
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if last != nil && !quotedINI(last.raw) {
+			lastline := last.raw[strings.LastIndex(last.raw, "\n")+1:]
+			deeper := trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, ";") && indentOf(line) > indent
+			if continued(strings.TrimSpace(lastline)) || deeper {
+				last.raw += "\n" + line
+				last.Value = unquoteINI(last.raw)
+				continue
+			}
+		}
+		last = nil
+
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+			comments = append(comments, line)
+		case strings.HasPrefix(line, "["):
+			name, style, inherits := parseSectionName(line)
+			active = doc.Section(name)
+			if active == nil {
+				active = &DocumentSection{Name: name, style: style}
+				doc.Sections = append(doc.Sections, active)
+			}
+			if inherits != "" {
+				active.inherits = inherits
+			}
+			active.Comments = append(active.Comments, comments...)
+			comments = nil
+		default:
+			key, value := parseOption(line)
+			entry := &Entry{Key: key, Value: unquoteINI(value), Comments: comments, raw: value, repeated: active.Entry(key) != nil}
+			active.Entries = append(active.Entries, entry)
+			if strings.ContainsAny(line, "=:") {
+				last, indent = entry, indentOf(line)
+			}
+			comments = nil
+		}
+	}
+
+	doc.Footer = comments
+
+	return doc, scanner.Err()
+}
+
+func (iniFormat) Encode(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, sec := range doc.Sections {
+		writeComments(&buf, sec.Comments)
+		if sec.Name != "" {
+			buf.WriteString(formatSectionName(sec.Name, sec.style, sec.inherits) + "\n")
+		}
+		for _, entry := range sec.Entries {
+			writeComments(&buf, entry.Comments)
+			switch {
+			case entry.raw != "" && unquoteINI(entry.raw) == entry.Value:
+				buf.WriteString(entry.Key + "=" + entry.raw + "\n")
+			case entry.Value != "":
+				buf.WriteString(entry.Key + "=" + quoteINI(entry.Value) + "\n")
+			default:
+				buf.WriteString(entry.Key + "\n")
+			}
+		}
+	}
+	writeComments(&buf, doc.Footer)
+
+	return buf.Bytes(), nil
+}
+
+// parseOption 함수는 한 줄을 key와 value로 분리합니다.
+// 구분자는 '='를 우선하며, 없을 경우 ':'를 사용합니다.
+func parseOption(line string) (key, value string) {
+	if i := strings.Index(line, "="); i != -1 {
+		return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+	}
+	if i := strings.Index(line, ":"); i != -1 {
+		return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+	}
+	return strings.TrimSpace(line), ""
+}
+
+// quotedINI 함수는 value가 큰따옴표로 감싸진 하나의 값인지 확인합니다.
+func quotedINI(raw string) bool {
+	return len(raw) > 1 && raw[0] == '"' && closingQuote(raw[1:], '"') == len(raw)-2
+}
+
+// unquoteINI 함수는 파일에 기록된 value의 표현을 실제 value로 변환합니다.
+// 큰따옴표로 감싸진 value는 이스케이프를 해석하며, 여러 줄의 value는 이어진 방식에 따라 연결합니다.
+func unquoteINI(raw string) string {
+	if quotedINI(raw) {
+		return unescapeEnv(raw[1 : len(raw)-1])
+	}
+
+	lines := strings.Split(raw, "\n")
+	value := strings.TrimSpace(lines[0])
+	for _, line := range lines[1:] {
+		switch {
+		case continued(value):
+			value = strings.TrimRight(value[:len(value)-1], " \t") + " " + strings.TrimSpace(line)
+		case value == "":
+			value = strings.TrimSpace(line)
+		default:
+			value += "\n" + strings.TrimSpace(line)
+		}
+	}
+	return value
+}
+
+// quoteINI 함수는 value를 파일에 기록할 표현으로 변환합니다.
+// 그대로 기록하면 다르게 읽히는 value만 큰따옴표로 감싸고 이스케이프합니다.
+func quoteINI(value string) string {
+	plain := strings.TrimSpace(value) == value && !strings.HasPrefix(value, `"`) && !continued(value)
+	for _, char := range value {
+		if char < ' ' && char != '\t' || char == 0x7f {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// indentOf 함수는 줄의 들여쓰기 문자 수를 반환합니다.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// parseSectionName 함수는 section 헤더에서 section 이름과 상속 section의 이름을 추출합니다.
+// git 형식의 따옴표 subsection은 점(.)으로 연결된 이름으로 변환하며, 헤더를 다시 기록할 수 있도록 style을 함께 반환합니다.
+// =======================================
+//
+// [server.web]		--> server.web
+// [remote "origin"]	--> remote.origin (style : quoted)
+// [branch "fix \"a\""]	--> branch.fix "a" (style : quoted)
+// [production : base]	--> production (inherits : base)
+// [program:web]		--> program:web
+//
+// =======================================
+func parseSectionName(line string) (name, style, inherits string) {
+	inner := strings.TrimSpace(line)
+	inner = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(inner, "["), "]"))
+
+	// 상속은 앞뒤에 공백이 있는 ':' 만 인식하므로 [program:web] 같은 이름은 그대로 사용합니다.
+	if colon := strings.LastIndex(inner, ":"); colon > 0 && colon < len(inner)-1 && colon > strings.LastIndex(inner, "\"") &&
+		strings.ContainsAny(inner[colon-1:colon], " \t") && strings.ContainsAny(inner[colon+1:colon+2], " \t") {
+		inner, inherits = strings.TrimSpace(inner[:colon]), strings.TrimSpace(inner[colon+1:])
+	}
+
+	quote := strings.Index(inner, "\"")
+	if quote == -1 || !strings.HasSuffix(inner, "\"") || quote == len(inner)-1 {
+		return strings.Trim(inner, " []"), "", inherits
+	}
+
+	var sub strings.Builder
+	escaped := false
+	for _, char := range inner[quote+1 : len(inner)-1] {
+		if !escaped && char == '\\' {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sub.WriteRune(char)
+	}
+
+	base := strings.TrimSpace(inner[:quote])
+	if base == "" {
+		return sub.String(), "", inherits
+	}
+	return base + "." + sub.String(), styleQuoted, inherits
+}
+
+// formatSectionName 함수는 section 이름을 section 헤더로 변환합니다.
+// 따옴표 subsection으로 읽어들인 section은 같은 형식으로 기록하며, 상속 section이 있을 경우 함께 기록합니다.
+func formatSectionName(name, style, inherits string) string {
+	header := name
+	if dot := strings.Index(name, "."); style == styleQuoted && dot != -1 {
+		header = name[:dot] + " \"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(name[dot+1:]) + "\""
+	}
+	if inherits != "" {
+		header += " : " + inherits
+	}
+	return "[" + header + "]"
+}
+
+func writeComments(buf *bytes.Buffer, comments []string) {
+	for _, comment := range comments {
+		buf.WriteString(comment + "\n")
+	}
+}
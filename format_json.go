@@ -0,0 +1,161 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// jsonFormat 구조체는 JSON 형식을 읽고 씁니다.
+// 최상위 값은 object여야 하며, key의 순서는 파일에 기록된 순서를 유지합니다.
+// JSON은 주석을 지원하지 않으므로 주석은 저장되지 않습니다.
+type jsonFormat struct{}
+
+func (jsonFormat) Name() string { return "json" }
+
+func (jsonFormat) Extensions() []string { return []string{".json"} }
+
+func (jsonFormat) AllowGlobal() bool { return true }
+
+func (jsonFormat) Decode(data []byte) (*Document, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return flatten(newTree()), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := value.(*tree)
+	if !ok {
+		return nil, errors.New("top-level value must be an object")
+	}
+	return flatten(root), nil
+}
+
+func (jsonFormat) Encode(doc *Document) ([]byte, error) {
+	root, err := unflatten(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, root, ""); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// decodeJSON 함수는 object의 key 순서를 유지하며 JSON 값을 읽어들입니다.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			t := newTree()
+			for dec.More() {
+				key, kerr := dec.Token()
+				if kerr != nil {
+					return nil, kerr
+				}
+				value, verr := decodeJSON(dec)
+				if verr != nil {
+					return nil, verr
+				}
+				t.set(fmt.Sprint(key), value)
+			}
+			_, err = dec.Token()
+			return t, err
+		case '[':
+			list := []interface{}{}
+			for dec.More() {
+				value, verr := decodeJSON(dec)
+				if verr != nil {
+					return nil, verr
+				}
+				list = append(list, value)
+			}
+			_, err = dec.Token()
+			return list, err
+		}
+		return nil, errors.New(fmt.Sprint("unexpected delimiter ", token))
+	case json.Number:
+		if value, ierr := token.Int64(); ierr == nil {
+			return value, nil
+		}
+		return token.Float64()
+	default:
+		return token, nil
+	}
+}
+
+// encodeJSON 함수는 tree의 key 순서를 유지하며 JSON 값을 기록합니다.
+func encodeJSON(buf *bytes.Buffer, value interface{}, indent string) error {
+	switch value := value.(type) {
+	case *tree:
+		if len(value.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, key := range value.keys {
+			buf.WriteString(indent + "  ")
+			if err := encodeJSON(buf, key, ""); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := encodeJSON(buf, value.values[key], indent+"  "); err != nil {
+				return err
+			}
+			if i != len(value.keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	case []interface{}:
+		if len(value) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range value {
+			buf.WriteString(indent + "  ")
+			if err := encodeJSON(buf, item, indent+"  "); err != nil {
+				return err
+			}
+			if i != len(value)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+	default:
+		var scalar bytes.Buffer
+		enc := json.NewEncoder(&scalar)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(value); err != nil {
+			return err
+		}
+		buf.WriteString(strings.TrimSuffix(scalar.String(), "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// propertiesFormat 구조체는 Java .properties 형식을 읽고 씁니다.
+// key의 마지막 점(.)을 기준으로 section과 key를 나누며, 점이 없는 key는 global section에 위치합니다.
+// =======================================
+//
+// # comment
+// ! comment
+// a.b.c=v
+// name : value
+// long=first \
+// second
+//
+// -->
+// [a.b]
+// c=v
+//
+// 구분자는 '=', ':' 또는 공백이며, \t \n \r \f \uXXXX 및 \= \: \# \! \  를 해석합니다.
+// 줄 끝의 '\' 는 다음 줄과 이어지며, 다음 줄의 앞 공백은 제거됩니다.
+//
+// =======================================
+type propertiesFormat struct{}
+
+func (propertiesFormat) Name() string { return "properties" }
+
+func (propertiesFormat) Extensions() []string { return []string{".properties"} }
+
+func (propertiesFormat) AllowGlobal() bool { return true }
+
+func (propertiesFormat) Decode(data []byte) (*Document, error) {
+	doc := &Document{}
+	doc.AddSection("")
+
+	var comments []string
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			comments = append(comments, lines[i])
+			continue
+		}
+
+		start := i
+		for continued(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		raw := strings.Join(lines[start:i+1], "\n")
+		key, value := parseProperty(raw)
+
+		name, option := "", key
+		if dot := strings.LastIndex(key, "."); dot >= 0 {
+			name, option = key[:dot], key[dot+1:]
+		}
+
+		doc.order = append(doc.order, key)
+
+		sec := doc.AddSection(name)
+		if entry := sec.Entry(option); entry != nil {
+			entry.Value, entry.raw, entry.repeated = value, raw, true
+			entry.Comments = append(entry.Comments, comments...)
+		} else {
+			sec.Entries = append(sec.Entries, &Entry{Key: option, Value: value, Comments: comments, raw: raw})
+		}
+		comments = nil
+	}
+
+	doc.Footer = comments
+
+	return doc, nil
+}
+
+func (propertiesFormat) Encode(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+
+	write := func(comments []string) {
+		for _, comment := range comments {
+			trimmed := strings.TrimSpace(comment)
+			if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+				buf.WriteString(comment + "\n")
+			} else {
+				buf.WriteString("# " + strings.TrimSpace(strings.TrimLeft(trimmed, ";")) + "\n")
+			}
+		}
+	}
+
+	written := map[*DocumentSection]bool{}
+	for _, line := range propertyLines(doc) {
+		if !written[line.sec] {
+			write(line.sec.Comments)
+			written[line.sec] = true
+		}
+		write(line.entry.Comments)
+		// 변경되지 않은 entry는 원래의 구분자와 이스케이프를 그대로 기록합니다.
+		if key, value := parseProperty(line.entry.raw); line.entry.raw != "" && key == joinSection(line.sec.Name, line.entry.Key) && value == line.entry.Value {
+			buf.WriteString(line.entry.raw + "\n")
+			continue
+		}
+		buf.WriteString(escapeProperty(joinSection(line.sec.Name, line.entry.Key), true))
+		buf.WriteString("=")
+		buf.WriteString(escapeProperty(line.entry.Value, false))
+		buf.WriteString("\n")
+	}
+	for _, sec := range doc.Sections {
+		if !written[sec] {
+			write(sec.Comments)
+		}
+	}
+	write(doc.Footer)
+
+	return buf.Bytes(), nil
+}
+
+// propertyLine 구조체는 .properties 파일의 한 줄에 기록될 entry입니다.
+type propertyLine struct {
+	sec      *DocumentSection
+	entry    *Entry
+	position int
+}
+
+// propertyLines 함수는 모든 entry를 읽어들인 파일의 줄 순서대로 반환합니다.
+// 새로 추가된 entry는 같은 section의 마지막 기존 entry 뒤에, section이 새로 추가된 경우 파일 끝에 위치합니다.
+func propertyLines(doc *Document) []propertyLine {
+	index := make(map[string]int, len(doc.order))
+	for i, key := range doc.order {
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	var lines []propertyLine
+	for _, sec := range doc.Sections {
+		position := len(doc.order)
+		for _, entry := range sec.Entries {
+			if i, ok := index[joinSection(sec.Name, entry.Key)]; ok {
+				position = i
+			}
+			lines = append(lines, propertyLine{sec: sec, entry: entry, position: position})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].position < lines[j].position })
+	return lines
+}
+
+// parseProperty 함수는 이어진 줄을 포함한 원래의 표현을 key와 value로 분리합니다.
+func parseProperty(raw string) (string, string) {
+	lines := strings.Split(raw, "\n")
+
+	line := strings.TrimLeft(lines[0], " \t\f")
+	for _, next := range lines[1:] {
+		line = line[:len(line)-1] + strings.TrimLeft(next, " \t\f")
+	}
+	if continued(line) {
+		line = line[:len(line)-1]
+	}
+	return splitProperty(line)
+}
+
+// continued 함수는 줄 끝에 이스케이프되지 않은 '\' 가 있는지 확인합니다.
+func continued(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitProperty 함수는 한 줄을 key와 value로 분리하고 이스케이프를 해석합니다.
+func splitProperty(line string) (key, value string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	return unescapeProperty(line[:end]), unescapeProperty(rest)
+}
+
+func unescapeProperty(value string) string {
+	var buf strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			buf.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 't':
+			buf.WriteByte('\t')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'u':
+			if i+4 < len(value) {
+				if code, err := strconv.ParseUint(value[i+1:i+5], 16, 32); err == nil {
+					buf.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			buf.WriteByte('u')
+		default:
+			buf.WriteByte(value[i])
+		}
+	}
+	return buf.String()
+}
+
+// escapeProperty 함수는 key 또는 value를 .properties 표현식으로 변환합니다.
+// key는 구분자와 주석 기호를, value는 앞 공백을 이스케이프합니다.
+func escapeProperty(value string, key bool) string {
+	var buf strings.Builder
+	for i, r := range value {
+		switch r {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case ' ':
+			if key || i == 0 {
+				buf.WriteString(`\ `)
+			} else {
+				buf.WriteRune(r)
+			}
+		case '=', ':', '#', '!':
+			if key || i == 0 {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
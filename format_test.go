@@ -0,0 +1,280 @@
+package conf4g
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFormatFunction(t *testing.T) {
+
+	/*
+		FormatFor(path)
+
+		FormatFor("config/app.toml")
+
+		--> TOML
+
+		configdata : app.toml
+
+		[server.web]
+		port = 80
+
+		variable.Find("server.web", "port")
+
+		--> 80
+	*/
+
+	Convey("Format Function", t, func() {
+		Convey("Format Select", func() {
+			So(FormatFor("config/app.ini").Name(), ShouldEqual, "ini")
+			So(FormatFor("config/app.json").Name(), ShouldEqual, "json")
+			So(FormatFor("config/app.YML").Name(), ShouldEqual, "yaml")
+			So(FormatFor("config/app.toml").Name(), ShouldEqual, "toml")
+			So(FormatFor("config/app").Name(), ShouldEqual, "ini")
+
+			conf := makeTestConfig("app.ini")
+			conf.SetFormat(JSON)
+			So(conf.GetFormat().Name(), ShouldEqual, "json")
+		})
+
+		Convey("Format TOML", func() {
+			path := filepath.Join(t.TempDir(), "app.toml")
+			writeTestFile(path, "name = \"app\"\n\n[server.web]\nport = 80\nhosts = [\"a\", \"b\"]\n\n[database]\nuser = \"root\"\n")
+			conf := makeTestConfig(path)
+
+			So(conf.Find("", "name"), ShouldEqual, "app")
+			So(conf.Find("server.web", "port"), ShouldEqual, "80")
+			So(conf.Find("server.web", "hosts"), ShouldEqual, "a, b")
+			So(conf.GetSectionList(), ShouldHaveLength, 2)
+
+			So(conf.Write("server.web", "port", "8080"), ShouldBeNil)
+			So(conf.Write("server.api", "port", "9090"), ShouldBeNil)
+			So(conf.DeleteSection("database"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "name = \"app\"\n\n[server.web]\nport = 8080\nhosts = [\"a\", \"b\"]\n\n[server.api]\nport = \"9090\"\n")
+		})
+
+		Convey("Format JSON", func() {
+			path := filepath.Join(t.TempDir(), "app.json")
+			writeTestFile(path, `{"server": {"web": {"port": 80, "tls": true}}, "database": {"user": "root"}}`)
+			conf := makeTestConfig(path)
+
+			So(conf.Find("server.web", "tls"), ShouldEqual, "true")
+			So(conf.Find("database", "user"), ShouldEqual, "root")
+
+			So(conf.Write("server.web", "tls", "false"), ShouldBeNil)
+			So(conf.DeleteValue("database", "user"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "{\n  \"server\": {\n    \"web\": {\n      \"port\": 80,\n      \"tls\": false\n    }\n  },\n  \"database\": {}\n}\n")
+		})
+
+		Convey("Format JSON Null Order", func() {
+			path := filepath.Join(t.TempDir(), "app.json")
+			content := "{\n  \"server\": {\n    \"port\": 80\n  },\n  \"n\": null,\n  \"name\": \"app\"\n}\n"
+			writeTestFile(path, content)
+			conf := makeTestConfig(path)
+
+			So(conf.Write("server", "port", "8080"), ShouldBeNil)
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, strings.Replace(content, "80", "8080", 1))
+
+			So(conf.Write("", "n", "set"), ShouldBeNil)
+			data, _ = os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "\"n\": \"set\",\n  \"name\"")
+		})
+
+		Convey("Format YAML", func() {
+			path := filepath.Join(t.TempDir(), "app.yaml")
+			writeTestFile(path, "# servers\nserver:\n  web:\n    port: 80\nlist:\n  - name: a\n  - name: b\n")
+			conf := makeTestConfig(path)
+
+			So(conf.Find("server.web", "port"), ShouldEqual, "80")
+			So(conf.Find("list.1", "name"), ShouldEqual, "b")
+
+			So(conf.Write("list.1", "name", "c"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "# servers\nserver:\n  web:\n    port: 80\nlist:\n  - name: a\n  - name: c\n")
+		})
+
+		Convey("Format Env", func() {
+			path := filepath.Join(t.TempDir(), ".env")
+			writeTestFile(path, "# database\nexport DB_HOST=localhost # inline\nDB_PASS='p@ss $word'\nDB_NOTE=\"first\\nsecond\"\nDB_MULTI=\"a\nb\"\n\n# end\n")
+			conf := makeTestConfig(path)
+
+			So(conf.GetFormat().Name(), ShouldEqual, "env")
+			So(conf.Find("", "DB_HOST"), ShouldEqual, "localhost")
+			So(conf.Find("", "DB_PASS"), ShouldEqual, "p@ss $word")
+			So(conf.Find("", "DB_NOTE"), ShouldEqual, "first\nsecond")
+			So(conf.Find("", "DB_MULTI"), ShouldEqual, "a\nb")
+			So(conf.GetSectionList(), ShouldBeNil)
+
+			So(conf.Write("", "DB_HOST", "db server"), ShouldBeNil)
+			So(conf.Write("", "DB_PORT", "5432"), ShouldBeNil)
+			So(conf.Write("Section001", "Key001", "Value001"), ShouldNotBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "# database\nexport DB_HOST=\"db server\"\nDB_PASS='p@ss $word'\nDB_NOTE=\"first\\nsecond\"\nDB_MULTI=\"a\nb\"\nDB_PORT=5432\n\n# end\n")
+		})
+
+		Convey("Format Env Preserve", func() {
+			path := filepath.Join(t.TempDir(), ".env")
+			content := "A=1 # the a\nB=\"x y\" # trailing\nURL=http://h/${PATH_PART}\nexport C='c'\n"
+			writeTestFile(path, content)
+			conf := makeTestConfig(path)
+
+			So(conf.Find("", "A"), ShouldEqual, "1")
+			So(conf.Find("", "B"), ShouldEqual, "x y")
+			So(conf.Write("", "NEW", "v"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, content+"NEW=v\n")
+
+			So(conf.Write("", "A", "2 3"), ShouldBeNil)
+			data, _ = os.ReadFile(path)
+			So(string(data), ShouldStartWith, "A=\"2 3\"\nB=\"x y\" # trailing\n")
+		})
+
+		Convey("Format Properties", func() {
+			path := filepath.Join(t.TempDir(), "app.properties")
+			writeTestFile(path, "! server\nserver.web.port = 80\nserver.web.name:web\\\n    server\nname app\nkey\\=with\\:sep=\\u0041\\tB\n")
+			conf := makeTestConfig(path)
+
+			So(conf.Find("server.web", "port"), ShouldEqual, "80")
+			So(conf.Find("server.web", "name"), ShouldEqual, "webserver")
+			So(conf.Find("", "name"), ShouldEqual, "app")
+			So(conf.Find("", "key=with:sep"), ShouldEqual, "A\tB")
+
+			So(conf.Write("server.web", "port", " 8080"), ShouldBeNil)
+			So(conf.Write("database", "user", "root"), ShouldBeNil)
+			So(conf.Write("server.web", "host", "localhost"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "! server\nserver.web.port=\\ 8080\nserver.web.name:web\\\n    server\nserver.web.host=localhost\nname app\nkey\\=with\\:sep=\\u0041\\tB\ndatabase.user=root\n")
+		})
+
+		Convey("Format Conflict", func() {
+			doc := &Document{}
+			doc.AddSection("").Set("server", "local")
+			doc.AddSection("server").Set("port", "80")
+
+			_, err := JSON.Encode(doc)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// quotingValue 타입은 따옴표, 이스케이프, 공백, 줄바꿈 문자가 자주 포함되는 임의의 value입니다.
+type quotingValue string
+
+func (quotingValue) Generate(r *rand.Rand, size int) reflect.Value {
+	alphabet := []rune(" \t\n\r\"'\\;#=:[]$`!ab한")
+
+	var buf strings.Builder
+	for i := r.Intn(size + 1); i >= 0; i-- {
+		buf.WriteRune(alphabet[r.Intn(len(alphabet))])
+	}
+	return reflect.ValueOf(quotingValue(buf.String()))
+}
+
+func TestQuotingFunction(t *testing.T) {
+
+	/*
+		[value]
+		quoted="  a\tb ; c\n"
+		joined=first \
+		second
+		lines=first
+		  second
+
+		variable.Find("value", "quoted")	--> "  a	b ; c\n"
+		variable.Find("value", "joined")	--> "first second"
+		variable.Find("value", "lines")	--> "first\nsecond"
+	*/
+
+	Convey("Quoting Function", t, func() {
+		Convey("Quoting Read", func() {
+			path := filepath.Join(t.TempDir(), "app.ini")
+			writeTestFile(path, "[value]\nquoted=\"  a\\tb ; c\\n\"\njoined=first \\\nsecond\nlines=first\n  second\nplain=a ; b # c\nsemi=;x\nhalf=\"a\" b\npath=C:\\dir\\\\\nkeyonly\n  indented\n")
+
+			conf, _ := New(WithPath(path))
+			So(conf.Find("value", "quoted"), ShouldEqual, "  a\tb ; c\n")
+			So(conf.Find("value", "joined"), ShouldEqual, "first second")
+			So(conf.Find("value", "lines"), ShouldEqual, "first\nsecond")
+			So(conf.Find("value", "plain"), ShouldEqual, "a ; b # c")
+			So(conf.Find("value", "semi"), ShouldEqual, ";x")
+			So(conf.Find("value", "half"), ShouldEqual, "\"a\" b")
+			So(conf.Find("value", "path"), ShouldEqual, `C:\dir\\`)
+			_, err := conf.ExistValue("value", "indented")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Quoting Preserve", func() {
+			path := filepath.Join(t.TempDir(), "app.ini")
+			content := "[value]\njoined=first \\\n  second\nlines=first\n  second\nquoted=\"a\"\n"
+			writeTestFile(path, content)
+
+			conf, _ := New(WithPath(path))
+			So(conf.Write("value", "added", " spaced "), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, content+"added=\" spaced \"\n")
+		})
+
+		Convey("Quoting Document Round Trip", func() {
+			for _, format := range []Format{INI, Env, Properties} {
+				roundtrip := func(value quotingValue) bool {
+					doc := &Document{}
+					doc.AddSection("").Set("key", string(value))
+					doc.AddSection("").Set("next", "value")
+
+					data, err := format.Encode(doc)
+					if err != nil {
+						return false
+					}
+					decoded, err := format.Decode(data)
+					if err != nil {
+						return false
+					}
+					global := decoded.Section("")
+					return global != nil && global.Entry("key") != nil && global.Entry("key").Value == string(value) && global.Entry("next").Value == "value"
+				}
+				So(quick.Check(roundtrip, &quick.Config{MaxCount: 2000}), ShouldBeNil)
+			}
+		})
+
+		Convey("Quoting Write Round Trip", func() {
+			path := filepath.Join(t.TempDir(), "app.ini")
+			writeTestFile(path, "[value]\nbefore=1\nkey=initial\nafter=2\n")
+			conf, _ := New(WithPath(path))
+
+			roundtrip := func(value quotingValue) bool {
+				if value == "" {
+					return true
+				}
+				if err := conf.Write("value", "key", string(value)); err != nil {
+					return false
+				}
+				return conf.Find("value", "key") == string(value) && conf.Find("value", "before") == "1" && conf.Find("value", "after") == "2"
+			}
+			So(quick.Check(roundtrip, &quick.Config{MaxCount: 300}), ShouldBeNil)
+
+			unicode := func(value string) bool {
+				if value == "" || strings.ContainsRune(value, 0xfffd) {
+					return true
+				}
+				return conf.Write("value", "key", value) == nil && conf.Find("value", "key") == value
+			}
+			So(quick.Check(unicode, &quick.Config{MaxCount: 300}), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,280 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlFormat 구조체는 TOML 형식을 읽고 씁니다.
+// table은 section으로, 중첩된 table은 점(.)으로 연결된 section 이름으로 표현됩니다.
+// 읽기는 다음의 라이브러리를 사용하며, 주석은 저장되지 않습니다.
+//
+// https://github.com/BurntSushi/toml
+type tomlFormat struct{}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (tomlFormat) Name() string { return "toml" }
+
+func (tomlFormat) Extensions() []string { return []string{".toml"} }
+
+func (tomlFormat) AllowGlobal() bool { return true }
+
+func (tomlFormat) Decode(data []byte) (*Document, error) {
+	var values map[string]interface{}
+
+	meta, err := toml.Decode(string(data), &values)
+	if err != nil {
+		return nil, err
+	}
+
+	root := newTree()
+
+	// MetaData.Keys는 파일에 기록된 순서대로 key를 반환합니다.
+	for _, key := range meta.Keys() {
+		value, ok := lookupNative(values, key)
+		if !ok {
+			continue
+		}
+
+		parent := root
+		for _, segment := range key[:len(key)-1] {
+			if parent = parent.child(segment); parent == nil {
+				break
+			}
+		}
+		if parent == nil {
+			continue
+		}
+
+		last := key[len(key)-1]
+		if _, ok := value.(map[string]interface{}); ok {
+			parent.child(last)
+			continue
+		}
+		if _, exist := parent.get(last); !exist {
+			parent.set(last, fromNative(value))
+		}
+	}
+	fillTree(root, values)
+
+	return flatten(root), nil
+}
+
+func (tomlFormat) Encode(doc *Document) ([]byte, error) {
+	root, err := unflatten(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := encodeTOML(&buf, nil, root, false); err != nil {
+		return nil, err
+	}
+	if footer := commentText(doc.Footer); len(footer) != 0 {
+		buf.WriteString("\n# " + strings.Join(footer, "\n# ") + "\n")
+	}
+
+	return bytes.TrimLeft(buf.Bytes(), "\n"), nil
+}
+
+// lookupNative 함수는 디코딩된 map에서 key 경로에 해당하는 값을 찾습니다.
+// 배열 내부의 table처럼 map으로 찾을 수 없는 경로는 false를 반환합니다.
+func lookupNative(values map[string]interface{}, key toml.Key) (interface{}, bool) {
+	var current interface{} = values
+	for _, segment := range key {
+		table, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = table[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// fillTree 함수는 MetaData.Keys에 포함되지 않은 inline table 등의 값을 tree에 추가합니다.
+func fillTree(t *tree, values map[string]interface{}) {
+	filled := fromNative(values).(*tree)
+	for _, key := range filled.keys {
+		value, ok := t.get(key)
+		if !ok {
+			t.set(key, filled.values[key])
+			continue
+		}
+		if sub, istree := value.(*tree); istree {
+			if native, ismap := values[key].(map[string]interface{}); ismap {
+				fillTree(sub, native)
+			}
+		}
+	}
+}
+
+// encodeTOML 함수는 table의 값을 먼저 기록한 후 하위 table을 기록합니다.
+// array가 true일 경우 table 배열의 원소로 기록합니다.
+func encodeTOML(buf *bytes.Buffer, path []string, t *tree, array bool) error {
+	scalars, tables := 0, 0
+	for _, key := range t.keys {
+		if isTOMLTable(t.values[key]) {
+			tables++
+		} else {
+			scalars++
+		}
+	}
+
+	if len(path) != 0 && (array || scalars != 0 || tables == 0) {
+		header := tomlPath(path)
+		buf.WriteString("\n")
+		if array {
+			buf.WriteString("[[" + header + "]]\n")
+		} else {
+			buf.WriteString("[" + header + "]\n")
+		}
+	}
+
+	for _, key := range t.keys {
+		value := t.values[key]
+		if isTOMLTable(value) {
+			continue
+		}
+		for _, comment := range commentText(t.comments[key]) {
+			buf.WriteString("# " + comment + "\n")
+		}
+		text, err := tomlValue(value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(tomlKey(key) + " = " + text + "\n")
+	}
+
+	for _, key := range t.keys {
+		switch value := t.values[key].(type) {
+		case *tree:
+			if err := encodeTOML(buf, append(append([]string{}, path...), key), value, false); err != nil {
+				return err
+			}
+		case []interface{}:
+			if !isTreeList(value) {
+				continue
+			}
+			for _, item := range value {
+				if err := encodeTOML(buf, append(append([]string{}, path...), key), item.(*tree), true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isTOMLTable(value interface{}) bool {
+	switch value := value.(type) {
+	case *tree:
+		return true
+	case []interface{}:
+		return isTreeList(value)
+	}
+	return false
+}
+
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlValue 함수는 값을 TOML 표현식으로 변환합니다.
+func tomlValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return `""`, nil
+	case string:
+		return tomlString(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		switch {
+		case math.IsNaN(value):
+			return "nan", nil
+		case math.IsInf(value, 1):
+			return "inf", nil
+		case math.IsInf(value, -1):
+			return "-inf", nil
+		}
+		text := strconv.FormatFloat(value, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		return text, nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return value.String(), nil
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			text, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = text
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case *tree:
+		return "", errors.New("unexpected table value")
+	}
+	return tomlString(fmt.Sprint(value)), nil
+}
+
+// tomlString 함수는 문자열을 TOML basic string으로 변환합니다.
+func tomlString(value string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
@@ -0,0 +1,325 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tree 구조체는 JSON, YAML, TOML의 중첩된 object/table을 순서대로 표현합니다.
+// comments는 각 key 앞에 위치한 주석입니다.
+type tree struct {
+	keys     []string
+	values   map[string]interface{}
+	comments map[string][]string
+}
+
+func newTree() *tree {
+	return &tree{values: map[string]interface{}{}, comments: map[string][]string{}}
+}
+
+func (t *tree) get(key string) (interface{}, bool) {
+	value, ok := t.values[key]
+	return value, ok
+}
+
+func (t *tree) set(key string, value interface{}) {
+	if _, ok := t.values[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.values[key] = value
+}
+
+// child 함수는 key에 해당하는 하위 tree를 반환하며, 존재하지 않을 경우 새로 추가합니다.
+// key가 이미 값으로 사용되고 있을 경우 nil을 반환합니다.
+func (t *tree) child(key string) *tree {
+	value, ok := t.get(key)
+	if !ok {
+		sub := newTree()
+		t.set(key, sub)
+		return sub
+	}
+	sub, _ := value.(*tree)
+	return sub
+}
+
+// flatten 함수는 중첩된 tree를 점(.)으로 연결된 section 이름의 Document로 변환합니다.
+// 최상위의 값은 global section에, object/table 배열은 "name.0", "name.1" section에 위치합니다.
+func flatten(root *tree) *Document {
+	doc := &Document{}
+	doc.AddSection("")
+
+	var walk func(prefix string, t *tree)
+	walk = func(prefix string, t *tree) {
+		if len(t.keys) == 0 {
+			doc.AddSection(prefix)
+		}
+
+		for _, key := range t.keys {
+			name := joinSection(prefix, key)
+			doc.order = append(doc.order, name)
+
+			if sub, ok := t.values[key].(*tree); ok {
+				if len(sub.keys) == 0 || doc.Section(name) == nil {
+					doc.AddSection(name).Comments = t.comments[key]
+				}
+				walk(name, sub)
+				continue
+			}
+
+			if list, ok := t.values[key].([]interface{}); ok && isTreeList(list) {
+				for i, item := range list {
+					doc.order = append(doc.order, joinSection(name, strconv.Itoa(i)))
+					walk(joinSection(name, strconv.Itoa(i)), item.(*tree))
+				}
+				continue
+			}
+
+			native := t.values[key]
+			if native == nil {
+				native = nullNative{}
+			}
+			sec := doc.AddSection(prefix)
+			sec.Entries = append(sec.Entries, &Entry{
+				Key:      key,
+				Value:    stringify(t.values[key]),
+				Comments: t.comments[key],
+				native:   native,
+			})
+		}
+	}
+	walk("", root)
+
+	// 값이 없는 중간 section은 제거하며, 주석은 다음 section으로 옮깁니다.
+	var comments []string
+	sections := doc.Sections[:0]
+	for _, sec := range doc.Sections {
+		if sec.Name == "" || len(sec.Entries) != 0 || !hasChild(doc, sec.Name) {
+			sec.Comments = append(comments, sec.Comments...)
+			comments = nil
+			sections = append(sections, sec)
+		} else {
+			comments = append(comments, sec.Comments...)
+		}
+	}
+	doc.Sections = sections
+
+	return doc
+}
+
+// unflatten 함수는 Document를 중첩된 tree로 변환합니다.
+// section 이름과 key가 같은 경로를 가리켜 중첩 구조로 표현할 수 없을 경우 에러를 반환합니다.
+func unflatten(doc *Document) (*tree, error) {
+	root := newTree()
+
+	for _, sec := range doc.Sections {
+		target := root
+		if sec.Name != "" {
+			// section의 주석은 이 section으로 인해 새로 생성된 최상위 key에 위치합니다.
+			commented := len(sec.Comments) == 0
+			segments := strings.Split(sec.Name, ".")
+			for i, segment := range segments {
+				parent := target
+				_, exist := parent.get(segment)
+				if target = target.child(segment); target == nil {
+					return nil, errors.New(fmt.Sprint("section ", sec.Name, " conflicts with key ", strings.Join(segments[:i+1], ".")))
+				}
+				if !commented && (!exist || i == len(segments)-1) {
+					parent.comments[segment] = sec.Comments
+					commented = true
+				}
+			}
+		}
+
+		for _, entry := range sec.Entries {
+			if value, ok := target.get(entry.Key); ok {
+				if _, istree := value.(*tree); istree {
+					return nil, errors.New(fmt.Sprint("key ", joinSection(sec.Name, entry.Key), " conflicts with section"))
+				}
+			}
+			if value, ok := target.get(entry.Key); ok && entry.repeated {
+				// 반복된 key는 배열로 변환합니다.
+				list, islist := value.([]interface{})
+				if !islist {
+					list = []interface{}{value}
+				}
+				target.set(entry.Key, append(list, entry.Native()))
+			} else {
+				target.set(entry.Key, entry.Native())
+			}
+			if len(entry.Comments) != 0 {
+				target.comments[entry.Key] = entry.Comments
+			}
+		}
+	}
+
+	if len(doc.order) != 0 {
+		index := make(map[string]int, len(doc.order))
+		for i, path := range doc.order {
+			// 다른 Format에서 읽어들인 경로는 상위 object를 포함하지 않으므로, 첫번째 하위 key의 위치를 사용합니다.
+			for name := path; ; name = name[:strings.LastIndex(name, ".")] {
+				if _, ok := index[name]; !ok {
+					index[name] = i
+				}
+				if !strings.Contains(name, ".") {
+					break
+				}
+			}
+		}
+		reorder(root, "", index)
+	}
+	return listify(root).(*tree), nil
+}
+
+// reorder 함수는 tree의 key를 읽어들인 파일의 순서대로 정렬합니다.
+// global section의 값이 object보다 먼저 추가되므로, 원래의 순서를 복원하기 위해 사용합니다.
+// 새로 추가된 key는 기존 key 뒤에 추가된 순서대로 위치합니다.
+func reorder(t *tree, prefix string, index map[string]int) {
+	position := func(key string) int {
+		if i, ok := index[joinSection(prefix, key)]; ok {
+			return i
+		}
+		return len(index)
+	}
+	sort.SliceStable(t.keys, func(i, j int) bool { return position(t.keys[i]) < position(t.keys[j]) })
+
+	for _, key := range t.keys {
+		if sub, ok := t.values[key].(*tree); ok {
+			reorder(sub, joinSection(prefix, key), index)
+		}
+	}
+}
+
+// listify 함수는 key가 0부터 연속된 숫자인 tree를 배열로 변환합니다.
+func listify(value interface{}) interface{} {
+	t, ok := value.(*tree)
+	if !ok {
+		return value
+	}
+
+	for _, key := range t.keys {
+		t.values[key] = listify(t.values[key])
+	}
+
+	if len(t.keys) == 0 {
+		return t
+	}
+	for i, key := range t.keys {
+		if key != strconv.Itoa(i) {
+			return t
+		}
+	}
+
+	list := make([]interface{}, len(t.keys))
+	for i, key := range t.keys {
+		list[i] = t.values[key]
+	}
+	return list
+}
+
+// fromNative 함수는 디코더가 반환한 map을 tree로 변환합니다.
+// map은 순서를 가지지 않으므로 key를 정렬하여 추가합니다.
+func fromNative(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		t := newTree()
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			t.set(key, fromNative(value[key]))
+		}
+		return t
+	case []map[string]interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = fromNative(item)
+		}
+		return list
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = fromNative(item)
+		}
+		return list
+	case int:
+		return int64(value)
+	case uint64:
+		return int64(value)
+	default:
+		return value
+	}
+}
+
+// stringify 함수는 값을 Document의 문자열 value로 변환합니다.
+// 배열은 쉼표로 구분된 문자열로 변환됩니다.
+func stringify(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case []interface{}:
+		parts := make([]string, len(value))
+		for i, item := range value {
+			parts[i] = stringify(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func isTreeList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if _, ok := item.(*tree); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func hasChild(doc *Document, name string) bool {
+	for _, sec := range doc.Sections {
+		if strings.HasPrefix(sec.Name, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinSection(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// commentText 함수는 주석 줄에서 주석 기호를 제거합니다.
+// 빈 줄은 제외됩니다.
+func commentText(comments []string) []string {
+	var texts []string
+	for _, comment := range comments {
+		comment = strings.TrimSpace(comment)
+		comment = strings.TrimSpace(strings.TrimLeft(comment, "#;!"))
+		if comment != "" {
+			texts = append(texts, comment)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,160 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlFormat 구조체는 YAML 형식을 읽고 씁니다.
+// 최상위 값은 mapping이어야 하며, key 앞의 주석은 Entry의 주석으로 유지됩니다.
+// 읽기/쓰기는 다음의 라이브러리를 사용합니다.
+//
+// https://github.com/go-yaml/yaml
+type yamlFormat struct{}
+
+func (yamlFormat) Name() string { return "yaml" }
+
+func (yamlFormat) Extensions() []string { return []string{".yaml", ".yml"} }
+
+func (yamlFormat) AllowGlobal() bool { return true }
+
+func (yamlFormat) Decode(data []byte) (*Document, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+
+	if node.Kind == 0 {
+		return flatten(newTree()), nil
+	}
+
+	value, err := fromYAML(&node)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := value.(*tree)
+	if !ok {
+		return nil, errors.New("top-level value must be a mapping")
+	}
+
+	doc := flatten(root)
+	if len(node.FootComment) != 0 {
+		doc.Footer = strings.Split(node.FootComment, "\n")
+	}
+	return doc, nil
+}
+
+func (yamlFormat) Encode(doc *Document) ([]byte, error) {
+	root, err := unflatten(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	node, nerr := toYAML(root)
+	if nerr != nil {
+		return nil, nerr
+	}
+	if footer := commentText(doc.Footer); len(footer) != 0 {
+		node.FootComment = "# " + strings.Join(footer, "\n# ")
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	enc.Close()
+
+	return buf.Bytes(), nil
+}
+
+// fromYAML 함수는 mapping의 key 순서를 유지하며 YAML node를 읽어들입니다.
+func fromYAML(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return newTree(), nil
+		}
+		return fromYAML(node.Content[0])
+	case yaml.MappingNode:
+		t := newTree()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			item, err := fromYAML(value)
+			if err != nil {
+				return nil, err
+			}
+			t.set(key.Value, item)
+			if key.HeadComment != "" {
+				t.comments[key.Value] = strings.Split(key.HeadComment, "\n")
+			}
+		}
+		return t, nil
+	case yaml.SequenceNode:
+		list := []interface{}{}
+		for _, content := range node.Content {
+			item, err := fromYAML(content)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case yaml.AliasNode:
+		return fromYAML(node.Alias)
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return fromNative(value), nil
+	}
+	return nil, errors.New(fmt.Sprint("unsupported node kind ", node.Kind))
+}
+
+// toYAML 함수는 tree를 YAML node로 변환합니다.
+func toYAML(value interface{}) (*yaml.Node, error) {
+	switch value := value.(type) {
+	case *tree:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range value.keys {
+			keynode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			if comments := commentText(value.comments[key]); len(comments) != 0 {
+				keynode.HeadComment = "# " + strings.Join(comments, "\n# ")
+			}
+			valuenode, err := toYAML(value.values[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, keynode, valuenode)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range value {
+			itemnode, err := toYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, itemnode)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
@@ -3,8 +3,10 @@ module feature/conf4g
 go 1.18
 
 require (
-	github.com/alyu/configparser v0.0.0-20191103060215-744e9a66e7bc
+	github.com/BurntSushi/toml v1.6.0
 	github.com/smartystreets/goconvey v1.7.2
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,292 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// include 지시자와 conf.d 디렉토리에 대한 설정값입니다.
+// =======================================
+//
+// global 영역의 include 키 또는 [include] section의 값으로 다른 파일을 포함합니다.
+// 여러 파일은 쉼표로 구분하며, 경로 앞에 '-'를 붙이면 파일이 없어도 무시합니다.
+// include 지시자는 INI Format에서만 사용하며, 다른 Format의 include 키는 일반 value입니다.
+//
+// include = base.ini, -local.ini
+//
+// [include]
+// common = common.ini
+//
+// =======================================
+const (
+	includeKey     = "include"
+	includeSection = "include"
+	dropinDir      = "conf.d"
+)
+
+// ErrIncludeCycle 에러는 include 지시자가 순환 참조를 일으킬 때 반환됩니다.
+var ErrIncludeCycle = errors.New("include : cycle detected")
+
+// ErrIncludeMissing 에러는 필수 include 파일이 존재하지 않을 때 반환됩니다.
+var ErrIncludeMissing = errors.New("include : missing file")
+
+// GetOrigin 함수는 지정된 section과 key의 value가 기록된 파일 경로를 반환합니다.
+// value가 존재하지 않을 경우 에러를 반환합니다.
+func (conf *Configuration) GetOrigin(section, key string) (string, error) {
+	conf.Read()
+	section, key = conf.resolve(section, key)
+
+	if targetsection, ok := conf.sections[section]; ok {
+		if origin, ok := targetsection.origin[key]; ok {
+			return origin, nil
+		}
+	}
+	return "", errors.New("GetOrigin : cannot find value")
+}
+
+// GetSourceList 함수는 병합에 사용된 모든 파일 경로를 적용 순서대로 반환합니다.
+// 순서가 뒤에 있는 파일의 value가 앞선 파일의 value를 덮어씁니다.
+func (conf *Configuration) GetSourceList() []string {
+	conf.Read()
+	return conf.sources
+}
+
+// SetOverridePath 함수는 Write가 항상 기록할 override 파일을 지정합니다.
+// 상대 경로는 config 파일의 폴더를 기준으로 하며, 공백값을 지정하면 override를 해제합니다.
+// override가 없을 경우 Write는 value가 기록된 파일에, 신규 value는 config 파일에 기록합니다.
+func (conf *Configuration) SetOverridePath(path string) error {
+	if path == "" {
+		conf.override = ""
+		return nil
+	}
+	if conf.confpath == "" {
+		return errors.New("SetOverridePath : missing configuration path")
+	}
+	conf.override = resolvePath(filepath.Dir(conf.confpath), path)
+	return nil
+}
+
+// target 함수는 section과 key를 기록할 파일 경로를 반환합니다.
+func (conf *Configuration) target(section, key string) string {
+	if conf.override != "" {
+		return conf.override
+	}
+	return conf.source(section, key)
+}
+
+// source 함수는 section과 key의 value가 기록된 파일 경로를 반환합니다.
+// value를 찾을 수 없을 경우 config 파일 경로를 반환합니다.
+func (conf *Configuration) source(section, key string) string {
+	if targetsection, ok := conf.sections[section]; ok {
+		if origin, ok := targetsection.origin[key]; ok {
+			return origin
+		}
+	}
+	return conf.confpath
+}
+
+// files 함수는 section을 포함하고 있는 모든 파일 경로를 반환합니다.
+// section을 찾을 수 없을 경우 config 파일 경로만 반환합니다.
+func (conf *Configuration) files(section string) []string {
+	if targetsection, ok := conf.sections[section]; ok && len(targetsection.files) != 0 {
+		return targetsection.files
+	}
+	return []string{conf.confpath}
+}
+
+// load 함수는 config 파일과 include 파일, conf.d 디렉토리, profile 파일을 순서대로 병합합니다.
+// config 파일이 존재하지 않을 경우 빈 설정으로 간주합니다.
+func (conf *Configuration) load() error {
+	conf.sections = map[string]section{}
+	conf.order = nil
+	conf.sources = nil
+
+	if _, fileerr := conf.stat(conf.confpath); fileerr != nil {
+		if conf.strict {
+			return fmt.Errorf("%w : %s", ErrConfigNotFound, conf.confpath)
+		}
+		return nil
+	}
+
+	if err := conf.merge(conf.confpath, nil); err != nil {
+		return err
+	}
+
+	dropins, _ := conf.storage().Glob(filepath.Join(filepath.Dir(conf.confpath), dropinDir, "*"+filepath.Ext(conf.confpath)))
+	sort.Strings(dropins)
+
+	for _, dropin := range dropins {
+		if ftype, _ := conf.stat(dropin); ftype != 1 {
+			continue
+		}
+		if err := conf.merge(dropin, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := conf.applyProfile(); err != nil {
+		return err
+	}
+
+	// 상속된 value에도 환경변수가 반영되도록 상속 전후로 환경변수를 적용합니다.
+	conf.overrideEnv()
+	if err := conf.inherit(); err != nil {
+		return err
+	}
+	conf.overrideEnv()
+	return nil
+}
+
+// merge 함수는 하나의 파일을 읽어 section 변수에 병합합니다.
+// include 된 파일을 먼저 병합한 후, 현재 파일의 value로 덮어씁니다.
+// stack은 순환 참조를 확인하기 위한 현재 include 경로입니다.
+func (conf *Configuration) merge(path string, stack []string) error {
+	for _, visited := range stack {
+		if visited == path {
+			return fmt.Errorf("%w : %s", ErrIncludeCycle, strings.Join(append(stack, path), " -> "))
+		}
+	}
+	stack = append(stack, path)
+
+	doc, derr := conf.readDocument(path)
+	if derr != nil {
+		return errors.New(fmt.Sprint("merge : config cannot read, ", derr))
+	}
+	if nerr := conf.checkNames(doc, path); nerr != nil {
+		return nerr
+	}
+
+	// include와 inherits 지시자는 INI Format에서만 해석합니다.
+	_, directive := conf.formatOf(path).(iniFormat)
+
+	var list []string
+	if directive {
+		list = includes(doc)
+	}
+
+	for _, include := range list {
+		optional := strings.HasPrefix(include, "-")
+		include = resolvePath(filepath.Dir(path), strings.TrimPrefix(include, "-"))
+
+		if ftype, fileerr := conf.stat(include); fileerr != nil || ftype != 1 {
+			if optional {
+				conf.warnf("conf4g: optional include %s not found (included from %s)", include, path)
+				continue
+			}
+			return fmt.Errorf("%w : %s (included from %s)", ErrIncludeMissing, include, path)
+		}
+		if err := conf.merge(include, stack); err != nil {
+			return err
+		}
+	}
+
+	conf.sources = append(conf.sources, path)
+
+	for _, tempsec := range doc.Sections {
+		if directive && tempsec.Name == includeSection || len(tempsec.Entries) == 0 && tempsec.Name == "" {
+			continue
+		}
+
+		name := conf.sectionName(tempsec.Name)
+		targetsection, ok := conf.sections[name]
+		if !ok {
+			targetsection = section{
+				name:      name,
+				data:      map[string]string{},
+				origin:    map[string]string{},
+				sensitive: map[string]bool{},
+			}
+			conf.order = append(conf.order, name)
+		}
+		targetsection.files = appendUnique(targetsection.files, path)
+		if tempsec.inherits != "" {
+			targetsection.parent = tempsec.inherits
+		}
+
+		occurrences := map[string][]string{}
+		for _, entry := range tempsec.Entries {
+			if directive && tempsec.Name == "" && entry.Key == includeKey {
+				continue
+			}
+			if directive && tempsec.Name != "" && entry.Key == inheritKey {
+				targetsection.parent = strings.TrimSpace(entry.Value)
+				continue
+			}
+			if conf.strict && entry.repeated {
+				return fmt.Errorf("merge : duplicate key %s in %s", joinSection(tempsec.Name, entry.Key), path)
+			}
+
+			key := keyName(conf.fold(), targetsection, entry.Key)
+			if _, exist := targetsection.data[key]; !exist {
+				targetsection.order = append(targetsection.order, key)
+			}
+			occurrences[key] = append(occurrences[key], entry.Value)
+			targetsection.data[key] = entry.Value
+			targetsection.origin[key] = path
+			if hasSensitiveTag(entry.Comments) {
+				targetsection.sensitive[key] = true
+			}
+		}
+		// 나중에 병합된 파일의 key는 이전 파일의 반복된 value를 모두 대체합니다.
+		for key, values := range occurrences {
+			setValues(&targetsection, key, values)
+		}
+		conf.sections[name] = targetsection
+	}
+	return conf.checkPermission(path, doc)
+}
+
+// includes 함수는 global 영역의 include 키와 [include] section에서 파일 목록을 추출합니다.
+func includes(doc *Document) []string {
+	var list []string
+
+	split := func(value string) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	if global := doc.Section(""); global != nil {
+		if entry := global.Entry(includeKey); entry != nil {
+			split(entry.Value)
+		}
+	}
+
+	if tempsec := doc.Section(includeSection); tempsec != nil {
+		for _, entry := range tempsec.Entries {
+			if entry.Value != "" {
+				split(entry.Value)
+			} else {
+				split(entry.Key)
+			}
+		}
+	}
+	return list
+}
+
+// resolvePath 함수는 상대 경로를 base 폴더 기준의 경로로 변환합니다.
+func resolvePath(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, exist := range list {
+		if exist == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
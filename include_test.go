@@ -0,0 +1,136 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// makeTestConfig 함수는 지정된 경로를 사용하는 초기화된 Configuration을 반환합니다.
+func makeTestConfig(path string) *Configuration {
+	conf := MakeConfig()
+	conf.Initialize()
+	conf.confpath = path
+	return conf
+}
+
+// writeTestFile 함수는 테스트용 파일을 생성합니다.
+func writeTestFile(path, content string) {
+	os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	os.WriteFile(path, []byte(content), 0644)
+}
+
+func TestIncludeFunction(t *testing.T) {
+
+	/*
+		configdata :
+
+		main.ini
+		include = base.ini, -optional.ini
+		[Section001]
+		Key001=Main
+
+		base.ini
+		[Section001]
+		Key001=Base
+		Key002=Base
+
+		conf.d/10-local.ini
+		[Section001]
+		Key002=Local
+
+		--> Section001 : Key001=Main, Key002=Local
+	*/
+
+	Convey("Include Function", t, func() {
+		dir := t.TempDir()
+		mainpath := filepath.Join(dir, "main.ini")
+
+		writeTestFile(mainpath, "include=base.ini, -optional.ini\n[Section001]\nKey001=Main\n")
+		writeTestFile(filepath.Join(dir, "base.ini"), "[Section001]\nKey001=Base\nKey002=Base\n[Section002]\nKey001=Base\n")
+		writeTestFile(filepath.Join(dir, "conf.d", "10-local.ini"), "[Section001]\nKey002=Local\n")
+
+		Convey("Include Merge", func() {
+			conf := makeTestConfig(mainpath)
+
+			So(conf.Read(), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Main")
+			So(conf.Find("Section001", "Key002"), ShouldEqual, "Local")
+			So(conf.Find("Section002", "Key001"), ShouldEqual, "Base")
+			So(len(conf.GetSectionList()), ShouldEqual, 2)
+			So(conf.GetSourceList(), ShouldResemble, []string{
+				filepath.Join(dir, "base.ini"),
+				mainpath,
+				filepath.Join(dir, "conf.d", "10-local.ini"),
+			})
+		})
+
+		Convey("Include Origin", func() {
+			conf := makeTestConfig(mainpath)
+
+			origin, err := conf.GetOrigin("Section002", "Key001")
+			So(err, ShouldBeNil)
+			So(origin, ShouldEqual, filepath.Join(dir, "base.ini"))
+
+			_, err = conf.GetOrigin("Section002", "Key002")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Include Write Origin", func() {
+			conf := makeTestConfig(mainpath)
+
+			So(conf.Write("Section002", "Key001", "Updated"), ShouldBeNil)
+			So(conf.Find("Section002", "Key001"), ShouldEqual, "Updated")
+
+			base, _ := os.ReadFile(filepath.Join(dir, "base.ini"))
+			So(string(base), ShouldContainSubstring, "Key001=Updated")
+		})
+
+		Convey("Include Write Override", func() {
+			conf := makeTestConfig(mainpath)
+
+			So(conf.SetOverridePath("conf.d/99-override.ini"), ShouldBeNil)
+			So(conf.Write("Section001", "Key001", "Override"), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Override")
+
+			origin, _ := conf.GetOrigin("Section001", "Key001")
+			So(origin, ShouldEqual, filepath.Join(dir, "conf.d", "99-override.ini"))
+		})
+
+		Convey("Include Delete Section", func() {
+			conf := makeTestConfig(mainpath)
+
+			So(conf.DeleteSection("Section001"), ShouldBeNil)
+			So(conf.Find("Section001", "Key002"), ShouldBeEmpty)
+		})
+
+		Convey("Include Missing", func() {
+			writeTestFile(mainpath, "include=missing.ini\n")
+			conf := makeTestConfig(mainpath)
+
+			So(errors.Is(conf.Read(), ErrIncludeMissing), ShouldBeTrue)
+		})
+
+		Convey("Include Cycle", func() {
+			writeTestFile(mainpath, "[include]\nnext=next.ini\n")
+			writeTestFile(filepath.Join(dir, "next.ini"), "include=main.ini\n")
+			conf := makeTestConfig(mainpath)
+
+			So(errors.Is(conf.Read(), ErrIncludeCycle), ShouldBeTrue)
+		})
+
+		Convey("Include Tree Format", func() {
+			yamlpath := filepath.Join(dir, "app.yaml")
+			writeTestFile(yamlpath, "include:\n  paths: /srv/a\nname: app\n")
+
+			conf, err := New(WithPath(yamlpath))
+			So(err, ShouldBeNil)
+			So(conf.Read(), ShouldBeNil)
+			So(conf.Find("include", "paths"), ShouldEqual, "/srv/a")
+			So(conf.Find("", "name"), ShouldEqual, "app")
+		})
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// section 상속에 대한 설정값입니다.
+// =======================================
+//
+// section 헤더 또는 inherits 키로 다른 section의 모든 key를 상속합니다.
+// inherits 키는 INI Format에서만 해석하며, 다른 Format에서는 일반 key입니다.
+// 상속한 section에 같은 key가 있을 경우 상속한 section의 value가 우선합니다.
+//
+// [base]
+// host=localhost
+// port=80
+//
+// [staging : base]		또는	[staging]
+// port=8080				inherits = base
+//
+// [production : staging]
+// host=prod.internal
+//
+// --> production : host=prod.internal, port=8080
+//
+// =======================================
+const inheritKey = "inherits"
+
+// ErrInheritCycle 에러는 section 상속이 순환 참조를 일으킬 때 반환됩니다.
+var ErrInheritCycle = errors.New("inherit : cycle detected")
+
+// ErrInheritMissing 에러는 상속할 section이 존재하지 않을 때 반환됩니다.
+var ErrInheritMissing = errors.New("inherit : missing section")
+
+// InheritedFrom 함수는 지정된 section과 key의 value가 정의된 section의 이름과 상속 여부를 반환합니다.
+// value가 section에 직접 기록되어 있을 경우 section 자신과 false를 반환합니다.
+// key가 존재하지 않을 경우 공백과 false를 반환합니다.
+// =======================================
+//
+// InheritedFrom("production", "port")	--> staging, true
+// InheritedFrom("production", "host")	--> production, false
+//
+// =======================================
+func (conf *Configuration) InheritedFrom(section, key string) (string, bool) {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	section, key = conf.resolve(section, key)
+	targetsection, ok := conf.sections[section]
+	if !ok {
+		return "", false
+	}
+	if from, inherited := targetsection.inherited[key]; inherited {
+		return from, true
+	}
+	if _, local := targetsection.data[key]; local {
+		return section, false
+	}
+	return "", false
+}
+
+// GetParent 함수는 section이 상속하는 section의 이름을 반환합니다.
+// 상속하지 않을 경우 공백값을 반환합니다.
+func (conf *Configuration) GetParent(section string) string {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	return conf.sections[conf.sectionName(section)].parent
+}
+
+// inherit 함수는 모든 section에 상속된 key를 추가합니다.
+// 상속할 section이 없거나 순환 참조가 있을 경우 에러를 반환합니다.
+func (conf *Configuration) inherit() error {
+	var names []string
+	for name := range conf.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	done := map[string]bool{}
+	for _, name := range names {
+		if err := conf.resolveParent(name, nil, done); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveParent 함수는 상위 section부터 차례로 상속된 key를 추가합니다.
+// stack은 순환 참조를 확인하기 위한 현재 상속 경로입니다.
+func (conf *Configuration) resolveParent(name string, stack []string, done map[string]bool) error {
+	if done[name] {
+		return nil
+	}
+	for _, visited := range stack {
+		if visited == name {
+			return fmt.Errorf("%w : %s", ErrInheritCycle, strings.Join(append(stack, name), " -> "))
+		}
+	}
+
+	targetsection := conf.sections[name]
+	if targetsection.parent == "" {
+		done[name] = true
+		return nil
+	}
+	targetsection.parent = conf.sectionName(targetsection.parent)
+
+	if _, ok := conf.sections[targetsection.parent]; !ok {
+		return fmt.Errorf("%w : %s (inherited by %s)", ErrInheritMissing, targetsection.parent, name)
+	}
+	if err := conf.resolveParent(targetsection.parent, append(stack, name), done); err != nil {
+		return err
+	}
+
+	base := conf.sections[targetsection.parent]
+	if targetsection.inherited == nil {
+		targetsection.inherited = map[string]string{}
+	}
+	for _, key := range orderedKeys(base) {
+		if _, local := targetsection.data[key]; local {
+			continue
+		}
+
+		from := targetsection.parent
+		if origin, ok := base.inherited[key]; ok {
+			from = origin
+		}
+		targetsection.data[key] = base.data[key]
+		targetsection.order = append(targetsection.order, key)
+		targetsection.inherited[key] = from
+		setValues(&targetsection, key, base.values[key])
+		if base.sensitive[key] {
+			targetsection.sensitive[key] = true
+		}
+	}
+
+	conf.sections[name] = targetsection
+	done[name] = true
+	return nil
+}
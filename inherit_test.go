@@ -0,0 +1,121 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInheritFunction(t *testing.T) {
+
+	/*
+		[base]
+		host=localhost
+		port=80
+
+		[production : base]
+		host=prod.internal
+
+		variable.Find("production", "port")	--> 80
+		variable.InheritedFrom("production", "port")	--> base, true
+	*/
+
+	Convey("Inherit Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[base]\nhost=localhost\nport=80\n; @sensitive\ntoken=abc\n"+
+			"[staging : base]\nport=8080\n"+
+			"[production]\ninherits = staging\nhost=prod.internal\n")
+
+		conf, _ := New(WithPath(path))
+
+		Convey("Inherit Find", func() {
+			So(conf.Read(), ShouldBeNil)
+			So(conf.Find("staging", "host"), ShouldEqual, "localhost")
+			So(conf.Find("production", "port"), ShouldEqual, "8080")
+			So(conf.Find("production", "host"), ShouldEqual, "prod.internal")
+			So(conf.Find("production", "inherits"), ShouldBeEmpty)
+
+			keys := conf.GetKeyList("production")
+			sort.Strings(keys)
+			So(keys, ShouldResemble, []string{"host", "port", "token"})
+
+			So(conf.IsSensitive("production", "token"), ShouldBeTrue)
+			So(conf.GetParent("production"), ShouldEqual, "staging")
+			So(conf.GetParent("base"), ShouldBeEmpty)
+		})
+
+		Convey("Inherit Origin", func() {
+			from, inherited := conf.InheritedFrom("production", "port")
+			So(from, ShouldEqual, "staging")
+			So(inherited, ShouldBeTrue)
+
+			from, inherited = conf.InheritedFrom("production", "token")
+			So(from, ShouldEqual, "base")
+			So(inherited, ShouldBeTrue)
+
+			from, inherited = conf.InheritedFrom("production", "host")
+			So(from, ShouldEqual, "production")
+			So(inherited, ShouldBeFalse)
+
+			from, _ = conf.InheritedFrom("production", "missing")
+			So(from, ShouldBeEmpty)
+		})
+
+		Convey("Inherit Write", func() {
+			So(conf.Write("base", "host", "db.internal"), ShouldBeNil)
+			So(conf.Find("production", "host"), ShouldEqual, "prod.internal")
+			So(conf.Find("staging", "host"), ShouldEqual, "db.internal")
+
+			So(conf.Write("staging", "host", "staging.internal"), ShouldBeNil)
+			So(conf.Find("production", "token"), ShouldEqual, "abc")
+			So(conf.DeleteValue("production", "port"), ShouldNotBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "[staging : base]\nport=8080\nhost=staging.internal\n")
+		})
+
+		Convey("Inherit Error", func() {
+			writeTestFile(path, "[a : b]\nkey=1\n[b : c]\n[c : a]\n")
+			So(errors.Is(conf.Read(), ErrInheritCycle), ShouldBeTrue)
+
+			writeTestFile(path, "[a : a]\nkey=1\n")
+			So(errors.Is(conf.Read(), ErrInheritCycle), ShouldBeTrue)
+
+			writeTestFile(path, "[a : missing]\nkey=1\n")
+			So(errors.Is(conf.Read(), ErrInheritMissing), ShouldBeTrue)
+		})
+
+		Convey("Inherit Colon Name", func() {
+			content := "[program:web]\ncommand=/usr/bin/web\n\n[program:worker]\ncommand=/usr/bin/worker\n"
+			writeTestFile(path, content)
+
+			So(conf.Read(), ShouldBeNil)
+			So(conf.Find("program:web", "command"), ShouldEqual, "/usr/bin/web")
+			So(conf.Find("program:worker", "command"), ShouldEqual, "/usr/bin/worker")
+			So(conf.GetParent("program:web"), ShouldBeEmpty)
+
+			So(conf.Write("program:web", "autostart", "true"), ShouldBeNil)
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "[program:web]\ncommand=/usr/bin/web\nautostart=true\n\n[program:worker]\ncommand=/usr/bin/worker\n")
+		})
+
+		Convey("Inherit Tree Format", func() {
+			jsonpath := filepath.Join(filepath.Dir(path), "deploy.json")
+			writeTestFile(jsonpath, `{"deploy": {"inherits": "none", "x": "1"}}`)
+			jsonconf := makeTestConfig(jsonpath)
+			So(jsonconf.Read(), ShouldBeNil)
+			So(jsonconf.Find("deploy", "inherits"), ShouldEqual, "none")
+			So(jsonconf.GetParent("deploy"), ShouldBeEmpty)
+
+			yamlpath := filepath.Join(filepath.Dir(path), "deploy.yaml")
+			writeTestFile(yamlpath, "base:\n  port: 80\nprod:\n  inherits: base\n")
+			yamlconf := makeTestConfig(yamlpath)
+			So(yamlconf.Find("prod", "inherits"), ShouldEqual, "base")
+			So(yamlconf.Find("prod", "port"), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package conf4g
+
+import "iter"
+
+// Setting 구조체는 All이 반환하는 하나의 [key=value]입니다.
+// secret value는 Find와 같이 복호화된 value입니다.
+type Setting struct {
+	Section string
+	Key     string
+	Value   string
+}
+
+// Sections 함수는 모든 section 이름을 파일에 기록된 순서대로 반환하는 iterator입니다.
+// section 헤더가 없는 global 영역은 포함하지 않습니다.
+// include와 conf.d 파일은 병합된 순서를 따르므로, include 파일의 section이 include한 파일의 section보다 먼저 위치합니다.
+// 반복을 시작할 때 config 파일을 한번만 읽어들이며, 반복 중의 변경은 반영되지 않습니다.
+// =======================================
+//
+//	for name := range conf.Sections() {
+//		fmt.Println(name)
+//	}
+//
+// =======================================
+func (conf *Configuration) Sections() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		conf.Read()
+
+		conf.mu.Lock()
+		names := conf.orderedSections()
+		conf.mu.Unlock()
+
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if !yield(name) {
+				return
+			}
+		}
+	}
+}
+
+// Entries 함수는 지정된 section의 모든 key와 value를 파일에 기록된 순서대로 반환하는 iterator입니다.
+// 반복된 key는 Find와 같이 마지막 value를 한번만 반환하며, 모든 value는 FindAll로 조회합니다.
+// section이 존재하지 않을 경우 아무것도 반환하지 않습니다.
+// =======================================
+//
+//	for key, value := range conf.Entries("database") {
+//		fmt.Println(key, value)
+//	}
+//
+// =======================================
+func (conf *Configuration) Entries(section string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		conf.Read()
+
+		conf.mu.Lock()
+		settings := conf.settings(conf.sectionName(section))
+		conf.mu.Unlock()
+
+		for _, setting := range settings {
+			if !yield(setting.Key, setting.Value) {
+				return
+			}
+		}
+	}
+}
+
+// All 함수는 global 영역을 포함한 모든 section의 [key=value]를 파일에 기록된 순서대로 반환하는 iterator입니다.
+// 반복을 시작할 때 config 파일을 한번만 읽어들이므로, 모든 value는 같은 시점의 내용입니다.
+// =======================================
+//
+//	for setting := range conf.All() {
+//		fmt.Println(setting.Section, setting.Key, setting.Value)
+//	}
+//
+// =======================================
+func (conf *Configuration) All() iter.Seq[Setting] {
+	return func(yield func(Setting) bool) {
+		conf.Read()
+
+		conf.mu.Lock()
+		var settings []Setting
+		for _, name := range conf.orderedSections() {
+			settings = append(settings, conf.settings(name)...)
+		}
+		conf.mu.Unlock()
+
+		for _, setting := range settings {
+			if !yield(setting) {
+				return
+			}
+		}
+	}
+}
+
+// settings 함수는 section의 모든 [key=value]를 파일에 기록된 순서대로 반환합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) settings(name string) []Setting {
+	targetsection, ok := conf.sections[name]
+	if !ok {
+		return nil
+	}
+
+	list := make([]Setting, 0, len(targetsection.data))
+	for _, key := range orderedKeys(targetsection) {
+		value, _ := conf.reveal(targetsection.data[key])
+		list = append(list, Setting{Section: name, Key: key, Value: value})
+	}
+	return list
+}
@@ -0,0 +1,87 @@
+//go:build go1.23
+
+package conf4g
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIteratorFunction(t *testing.T) {
+
+	/*
+		name=app
+		[zeta]
+		b=2
+		a=1
+		[alpha : zeta]
+		c=3
+
+		for name := range variable.Sections()		--> zeta, alpha
+		for key, value := range variable.Entries("zeta")	--> b=2, a=1
+		for setting := range variable.All()		--> name, zeta.b, zeta.a, alpha.c, alpha.b, alpha.a
+		(include 파일의 section과 key는 include한 파일보다 먼저 반환됩니다.)
+	*/
+
+	Convey("Iterator Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "include = extra.ini\nname=app\n[zeta]\nb=2\na=1\n[alpha : zeta]\nc=3\n[empty]\n")
+		writeTestFile(filepath.Join(dir, "extra.ini"), "[omega]\nz=26\n[zeta]\nx=0\n")
+
+		conf, _ := New(WithPath(path))
+
+		Convey("Sections", func() {
+			var names []string
+			for name := range conf.Sections() {
+				names = append(names, name)
+			}
+			So(names, ShouldResemble, []string{"omega", "zeta", "alpha", "empty"})
+
+			names = nil
+			for name := range conf.Sections() {
+				names = append(names, name)
+				break
+			}
+			So(names, ShouldResemble, []string{"omega"})
+		})
+
+		Convey("Entries", func() {
+			var keys, values []string
+			for key, value := range conf.Entries("zeta") {
+				keys = append(keys, key)
+				values = append(values, value)
+			}
+			So(keys, ShouldResemble, []string{"x", "b", "a"})
+			So(values, ShouldResemble, []string{"0", "2", "1"})
+
+			keys = nil
+			for key := range conf.Entries("alpha") {
+				keys = append(keys, key)
+			}
+			So(keys, ShouldResemble, []string{"c", "x", "b", "a"})
+
+			for range conf.Entries("none") {
+				t.Fatal("unexpected entry")
+			}
+		})
+
+		Convey("All", func() {
+			var settings []Setting
+			for setting := range conf.All() {
+				settings = append(settings, setting)
+				if setting.Section == "zeta" {
+					// 반복 중의 변경은 현재 반복에 반영되지 않습니다.
+					conf.Write("zeta", "b", "changed")
+				}
+			}
+			So(settings, ShouldHaveLength, 9)
+			So(settings[0], ShouldResemble, Setting{Section: "omega", Key: "z", Value: "26"})
+			So(settings[2], ShouldResemble, Setting{Section: "zeta", Key: "b", Value: "2"})
+			So(settings[4], ShouldResemble, Setting{Section: "", Key: "name", Value: "app"})
+			So(conf.Find("zeta", "b"), ShouldEqual, "changed")
+		})
+	})
+}
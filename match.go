@@ -0,0 +1,130 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NameMatch 타입은 section과 key 이름의 비교 방식입니다.
+// 비교 방식과 관계없이 파일에는 원래의 이름이 그대로 기록됩니다.
+// =======================================
+//
+// MatchExact		: Database != database
+// MatchIgnoreCase	: Database == database
+// MatchLoose		: Max Connections == max_connections == MAXCONNECTIONS
+//
+// =======================================
+type NameMatch int
+
+const (
+	// MatchExact 는 이름이 정확히 같아야 합니다.
+	MatchExact NameMatch = iota
+	// MatchIgnoreCase 는 대소문자를 구분하지 않습니다.
+	MatchIgnoreCase
+	// MatchLoose 는 대소문자를 구분하지 않으며, 공백과 밑줄(_)을 무시합니다.
+	MatchLoose
+)
+
+// ErrNameCollision 에러는 한 파일에 같은 이름으로 간주되는 section 또는 key가 서로 다르게 기록되어 있을 때 반환됩니다.
+var ErrNameCollision = errors.New("name : collision")
+
+// SetNameMatch 함수는 section과 key 이름의 비교 방식을 지정합니다.
+func (conf *Configuration) SetNameMatch(match NameMatch) {
+	conf.match = match
+}
+
+// GetNameMatch 함수는 section과 key 이름의 비교 방식을 반환합니다.
+func (conf *Configuration) GetNameMatch() NameMatch {
+	return conf.match
+}
+
+// fold 함수는 비교 방식에 따라 이름을 비교용으로 변환하는 함수를 반환합니다.
+// MatchExact일 경우 nil을 반환합니다.
+func (conf *Configuration) fold() func(string) string {
+	switch conf.match {
+	case MatchIgnoreCase:
+		return strings.ToLower
+	case MatchLoose:
+		return func(name string) string {
+			return strings.Map(func(char rune) rune {
+				if char == '_' || unicode.IsSpace(char) {
+					return -1
+				}
+				return unicode.ToLower(char)
+			}, name)
+		}
+	}
+	return nil
+}
+
+// sameName 함수는 fold에 따라 두 이름이 같은지 확인합니다. fold가 nil일 경우 정확히 비교합니다.
+func sameName(fold func(string) string, a, b string) bool {
+	return a == b || fold != nil && fold(a) == fold(b)
+}
+
+// resolve 함수는 section과 key를 이미 읽어들인 section과 key의 이름으로 변환합니다.
+// 같은 이름으로 간주되는 section 또는 key가 없을 경우 그대로 반환합니다.
+func (conf *Configuration) resolve(section, key string) (string, string) {
+	section = conf.sectionName(section)
+	return section, keyName(conf.fold(), conf.sections[section], key)
+}
+
+// sectionName 함수는 name과 같은 이름으로 간주되는 section의 이름을 반환합니다.
+func (conf *Configuration) sectionName(name string) string {
+	fold := conf.fold()
+	if _, ok := conf.sections[name]; ok || fold == nil {
+		return name
+	}
+	for existing := range conf.sections {
+		if fold(existing) == fold(name) {
+			return existing
+		}
+	}
+	return name
+}
+
+// keyName 함수는 section에서 key와 같은 이름으로 간주되는 key의 이름을 반환합니다.
+func keyName(fold func(string) string, targetsection section, key string) string {
+	if _, ok := targetsection.data[key]; ok || fold == nil {
+		return key
+	}
+	for existing := range targetsection.data {
+		if fold(existing) == fold(key) {
+			return existing
+		}
+	}
+	return key
+}
+
+// checkNames 함수는 한 파일에 같은 이름으로 간주되는 section 또는 key가 서로 다르게 기록되어 있는지 확인합니다.
+// 완전히 같은 이름의 반복된 key는 충돌로 간주하지 않습니다.
+func (conf *Configuration) checkNames(doc *Document, path string) error {
+	fold := conf.fold()
+	if fold == nil {
+		return nil
+	}
+
+	sections := map[string]string{}
+	for _, sec := range doc.Sections {
+		if previous, ok := sections[fold(sec.Name)]; ok && previous != sec.Name {
+			return fmt.Errorf("%w : section %s and %s in %s", ErrNameCollision, previous, sec.Name, path)
+		}
+		sections[fold(sec.Name)] = sec.Name
+
+		keys := map[string]string{}
+		for _, entry := range sec.Entries {
+			if previous, ok := keys[fold(entry.Key)]; ok && previous != entry.Key {
+				return fmt.Errorf("%w : key %s and %s in %s", ErrNameCollision, joinSection(sec.Name, previous), joinSection(sec.Name, entry.Key), path)
+			}
+			keys[fold(entry.Key)] = entry.Key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNameMatchFunction(t *testing.T) {
+
+	/*
+		[Database]
+		Host=localhost
+		Max_Connections=10
+
+		variable, _ := New(WithNameMatch(MatchIgnoreCase))
+		variable.Find("database", "host")		--> localhost
+
+		variable, _ := New(WithNameMatch(MatchLoose))
+		variable.Find("DATABASE", "max connections")	--> 10
+	*/
+
+	Convey("NameMatch Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "[Database]\nHost=localhost\nMax_Connections=10\n")
+
+		Convey("NameMatch Exact", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.GetNameMatch(), ShouldEqual, MatchExact)
+			So(conf.Find("Database", "Host"), ShouldEqual, "localhost")
+			So(conf.Find("database", "host"), ShouldBeEmpty)
+		})
+
+		Convey("NameMatch IgnoreCase", func() {
+			conf, _ := New(WithPath(path), WithNameMatch(MatchIgnoreCase))
+			So(conf.Find("database", "host"), ShouldEqual, "localhost")
+			So(conf.Find("DATABASE", "max_connections"), ShouldEqual, "10")
+			So(conf.Find("database", "max connections"), ShouldBeEmpty)
+			So(conf.GetKeyList("database"), ShouldHaveLength, 2)
+			So(conf.Section("database").Keys(), ShouldResemble, []string{"Host", "Max_Connections"})
+
+			So(conf.Write("database", "HOST", "db.internal"), ShouldBeNil)
+			So(conf.Write("DATABASE", "Port", "5432"), ShouldBeNil)
+			So(conf.Find("Database", "Host"), ShouldEqual, "db.internal")
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "[Database]\nHost=db.internal\nMax_Connections=10\nPort=5432\n")
+
+			So(conf.DeleteValue("database", "port"), ShouldBeNil)
+			_, err := conf.ExistValue("Database", "Port")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("NameMatch Sensitive", func() {
+			conf, _ := New(WithPath(path), WithNameMatch(MatchIgnoreCase))
+			conf.MarkSensitive("database", "host")
+			So(conf.IsSensitive("Database", "Host"), ShouldBeTrue)
+			So(conf.String(), ShouldNotContainSubstring, "localhost")
+		})
+
+		Convey("NameMatch Prefix", func() {
+			writeTestFile(path, "[Cache.Redis]\nHost=localhost\n[Cachet]\nHost=other\n")
+
+			conf, _ := New(WithPath(path), WithNameMatch(MatchIgnoreCase))
+			So(conf.Prefix("cache").Keys(), ShouldResemble, []string{"Redis.Host"})
+			So(conf.Prefix("cache").Get("redis.host"), ShouldEqual, "localhost")
+		})
+
+		Convey("NameMatch Loose", func() {
+			conf, _ := New(WithPath(path))
+			conf.SetNameMatch(MatchLoose)
+			So(conf.Find("database", "max connections"), ShouldEqual, "10")
+			So(conf.Find("data base", "MAXCONNECTIONS"), ShouldEqual, "10")
+		})
+
+		Convey("NameMatch Include", func() {
+			writeTestFile(path, "include = extra.ini\n[Database]\nHost=localhost\n")
+			writeTestFile(filepath.Join(dir, "extra.ini"), "[DATABASE]\nhost=extra\nuser=app\n")
+
+			conf, _ := New(WithPath(path), WithNameMatch(MatchIgnoreCase))
+			So(conf.GetSectionList(), ShouldResemble, []string{"DATABASE"})
+			So(conf.Find("database", "HOST"), ShouldEqual, "localhost")
+			So(conf.Find("database", "user"), ShouldEqual, "app")
+
+			So(conf.Write("Database", "User", "admin"), ShouldBeNil)
+			data, _ := os.ReadFile(filepath.Join(dir, "extra.ini"))
+			So(string(data), ShouldEqual, "[DATABASE]\nhost=extra\nuser=admin\n")
+		})
+
+		Convey("NameMatch Collision", func() {
+			writeTestFile(path, "[Database]\nHost=localhost\nhost=other\n")
+
+			conf, _ := New(WithPath(path))
+			So(conf.Read(), ShouldBeNil)
+
+			conf.SetNameMatch(MatchIgnoreCase)
+			err := conf.Read()
+			So(errors.Is(err, ErrNameCollision), ShouldBeTrue)
+
+			writeTestFile(path, "[Database]\nHost=a\n[database]\nPort=1\n")
+			So(errors.Is(conf.Read(), ErrNameCollision), ShouldBeTrue)
+
+			writeTestFile(path, "[Database]\nHost=a\nHost=b\n")
+			So(conf.Read(), ShouldBeNil)
+			So(conf.FindAll("database", "host"), ShouldResemble, []string{"a", "b"})
+		})
+	})
+}
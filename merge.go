@@ -0,0 +1,443 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// MergeStrategy 타입은 두 config의 같은 key에 서로 다른 value가 있을 때의 처리 방식입니다.
+type MergeStrategy int
+
+const (
+	// MergeSrcWins 는 src의 value를 사용합니다.
+	MergeSrcWins MergeStrategy = iota
+	// MergeDstWins 는 dst의 value를 유지합니다.
+	MergeDstWins
+	// MergeError 는 충돌이 있을 경우 아무것도 기록하지 않고 ErrMergeConflict를 반환합니다.
+	MergeError
+)
+
+// ErrMergeConflict 에러는 MergeError 방식의 section에서 충돌이 발생했을 때 반환됩니다.
+var ErrMergeConflict = errors.New("merge : conflict")
+
+// MergePolicy 구조체는 Merge의 충돌 처리 방식입니다.
+// Sections에 지정된 section은 Strategy 대신 해당 방식을 사용합니다.
+// Sections의 이름은 path.Match 형식의 패턴을 사용할 수 있습니다. (예: "server.*")
+type MergePolicy struct {
+	Strategy MergeStrategy
+	Sections map[string]MergeStrategy
+}
+
+// Conflict 구조체는 병합 중 발견된 하나의 충돌입니다.
+// 각 value는 존재하지 않을 경우 공백이며, 민감한 key의 value는 Redacted로 기록됩니다.
+// Base는 three-way 병합에서만 사용됩니다.
+type Conflict struct {
+	Section string
+	Key     string
+	Base    string
+	Dst     string
+	Src     string
+}
+
+func (conflict Conflict) String() string {
+	return fmt.Sprintf("%s : dst %q, src %q", joinSection(conflict.Section, conflict.Key), conflict.Dst, conflict.Src)
+}
+
+// mutation 구조체는 config 파일에 적용할 하나의 변경입니다.
+// delete가 true일 경우 value를 삭제합니다.
+// values가 있을 경우 value 대신 반복된 key의 모든 value를 순서대로 기록합니다.
+type mutation struct {
+	section string
+	key     string
+	value   string
+	values  []string
+	delete  bool
+}
+
+// Merge 함수는 src의 section과 key를 dst에 병합하고, 발견된 충돌 목록을 반환합니다.
+// dst에 없는 key는 추가되며, 양쪽에 서로 다른 value가 있을 경우 policy에 따라 처리합니다.
+// 병합한 내용은 dst 파일의 Document에 적용되므로 주석과 순서가 유지되며, 파일마다 한번만 저장되고 백업됩니다.
+// 저장 전에 모든 변경을 검증하므로, 유효하지 않은 변경이 있을 경우 아무것도 기록하지 않습니다.
+// MergeError 방식의 section에서 충돌이 있을 경우 아무것도 기록하지 않고 ErrMergeConflict를 반환합니다.
+// =======================================
+//
+// dst : [Server] Port=80, Host=a	src : [Server] Port=8080, Name=b
+//
+// MergeSrcWins	--> [Server] Port=8080, Host=a, Name=b
+// MergeDstWins	--> [Server] Port=80, Host=a, Name=b
+//
+// =======================================
+func Merge(dst, src *Configuration, policy MergePolicy) ([]Conflict, error) {
+	dst.Read()
+	src.Read()
+
+	var actions []mutation
+	var conflicts []Conflict
+	failed := false
+
+	for _, name := range sectionNames(src) {
+		target := dst.sections[name]
+
+		for _, key := range keyNames(src.sections[name]) {
+			values, ok := localValues(src.sections[name], key)
+			if !ok {
+				continue
+			}
+			action := mutation{section: name, key: key, value: values[len(values)-1], values: values}
+
+			current, ok := localValues(target, key)
+			if !ok {
+				actions = append(actions, action)
+				continue
+			}
+			if equalValues(current, values) {
+				continue
+			}
+
+			conflicts = append(conflicts, Conflict{Section: name, Key: key, Dst: dst.redactValues(name, key, current), Src: src.redactValues(name, key, values)})
+			switch policy.strategy(name) {
+			case MergeSrcWins:
+				actions = append(actions, action)
+			case MergeError:
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return conflicts, fmt.Errorf("Merge : %w, %d keys", ErrMergeConflict, len(conflicts))
+	}
+	if err := dst.commit(actions); err != nil {
+		return conflicts, errors.New(fmt.Sprint("Merge : ", err))
+	}
+	return conflicts, nil
+}
+
+// MergeThreeWay 함수는 공통 조상인 base를 기준으로 src의 변경을 dst에 병합하고, 충돌 목록을 반환합니다.
+// 한쪽에서만 변경된 key는 충돌 없이 반영되며, src에서 삭제된 key는 dst에서도 삭제됩니다.
+// 양쪽에서 서로 다르게 변경된 key만 충돌로 간주하여 policy에 따라 처리합니다.
+// Merge와 같이 모든 변경을 검증한 후 dst 파일마다 한번만 저장합니다.
+// =======================================
+//
+// base : Port=80		dst : Port=80, Host=a		src : Port=8080
+//
+// --> Port=8080, Host=a (충돌 없음)
+//
+// base : Port=80		dst : Port=81			src : Port=8080
+//
+// --> Port 충돌
+//
+// =======================================
+func MergeThreeWay(base, dst, src *Configuration, policy MergePolicy) ([]Conflict, error) {
+	base.Read()
+	dst.Read()
+	src.Read()
+
+	var actions []mutation
+	var conflicts []Conflict
+	failed := false
+
+	for _, name := range sectionNames(base, dst, src) {
+		keys := map[string]bool{}
+		for _, tempsec := range []section{base.sections[name], dst.sections[name], src.sections[name]} {
+			for key := range tempsec.data {
+				keys[key] = true
+			}
+		}
+
+		var sorted []string
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			// 상속된 key는 Merge와 같이 병합하지 않으므로 존재하지 않는 것으로 간주합니다.
+			ancestor, inbase := localValue(base.sections[name], key)
+			current, indst := localValue(dst.sections[name], key)
+			value, insrc := localValue(src.sections[name], key)
+
+			switch {
+			case indst == insrc && current == value:
+				// 양쪽이 동일합니다.
+				continue
+			case insrc == inbase && value == ancestor:
+				// src에서 변경되지 않았습니다.
+				continue
+			case indst == inbase && current == ancestor:
+				// dst에서 변경되지 않았으므로 src의 변경을 반영합니다.
+				actions = append(actions, mutation{section: name, key: key, value: value, delete: !insrc})
+				continue
+			}
+
+			conflicts = append(conflicts, Conflict{
+				Section: name,
+				Key:     key,
+				Base:    base.redact(name, key, ancestor),
+				Dst:     dst.redact(name, key, current),
+				Src:     src.redact(name, key, value),
+			})
+			switch policy.strategy(name) {
+			case MergeSrcWins:
+				actions = append(actions, mutation{section: name, key: key, value: value, delete: !insrc})
+			case MergeError:
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return conflicts, fmt.Errorf("MergeThreeWay : %w, %d keys", ErrMergeConflict, len(conflicts))
+	}
+	if err := dst.commit(actions); err != nil {
+		return conflicts, errors.New(fmt.Sprint("MergeThreeWay : ", err))
+	}
+	return conflicts, nil
+}
+
+// strategy 함수는 section에 적용할 MergeStrategy를 반환합니다.
+func (policy MergePolicy) strategy(section string) MergeStrategy {
+	if strategy, ok := policy.Sections[section]; ok {
+		return strategy
+	}
+
+	var patterns []string
+	for pattern := range policy.Sections {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, section); ok {
+			return policy.Sections[pattern]
+		}
+	}
+	return policy.Strategy
+}
+
+// commit 함수는 mutex의 Lock 함수를 사용하여 변경 목록을 config 파일에 기록합니다.
+func (conf *Configuration) commit(actions []mutation) error {
+	conf.Read()
+	conf.mu.Lock()
+
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+	}()
+
+	return conf.apply(actions)
+}
+
+// apply 함수는 변경 목록을 모두 검증한 후, 파일별로 하나의 Document에 순서대로 적용하여 한번씩 저장합니다.
+// 검증 또는 변환에 실패한 변경이 있을 경우 아무 파일도 기록하지 않습니다.
+// 감사 기록은 모든 파일을 저장한 후 변경마다 남깁니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) apply(actions []mutation) error {
+	type change struct {
+		operation, path, section, key, before, after string
+	}
+
+	if conf.confpath == "" {
+		return errors.New("missing configuration path")
+	}
+
+	var paths []string
+	var changes []change
+	docs := map[string]*Document{}
+
+	for _, action := range actions {
+		section, key := conf.resolve(action.section, action.key)
+		name := joinSection(action.section, action.key)
+
+		path := conf.target(section, key)
+		if action.delete {
+			path = conf.source(section, key)
+		}
+
+		switch {
+		case section == "" && !conf.allowGlobal(path):
+			return errors.New(fmt.Sprint(name, " : missing section"))
+		case key == "":
+			return errors.New(fmt.Sprint(name, " : missing key"))
+		case !action.delete && action.value == "":
+			return errors.New(fmt.Sprint(name, " : missing value"))
+		}
+		for _, value := range action.values {
+			if value == "" {
+				return errors.New(fmt.Sprint(name, " : missing value"))
+			}
+		}
+		if from, ok := conf.sections[section].inherited[key]; ok && action.delete {
+			return errors.New(fmt.Sprint(name, " : value is inherited from ", from))
+		}
+
+		doc, ok := docs[path]
+		if !ok {
+			if ftype, fileerr := conf.stat(path); fileerr != nil {
+				doc = conf.emptyDocument()
+			} else if ftype == 0 {
+				return errors.New(fmt.Sprint(name, " : target is directory"))
+			} else {
+				var derr error
+				if doc, derr = conf.readDocument(path); derr != nil {
+					return errors.New(fmt.Sprint(name, " : cannot read configuration ", derr))
+				}
+			}
+			docs[path] = doc
+			paths = append(paths, path)
+		}
+
+		before := conf.sections[section].data[key]
+		if action.delete {
+			sec := doc.Section(conf.docSection(section, key))
+			if sec == nil {
+				return errors.New(fmt.Sprint(name, " : cannot load section"))
+			}
+			sec.Delete(key)
+			changes = append(changes, change{AuditDeleteValue, path, section, key, before, ""})
+			continue
+		}
+		sec := doc.AddSection(conf.docSection(section, key))
+		if len(action.values) < 2 {
+			sec.Set(key, action.value)
+			changes = append(changes, change{AuditWrite, path, section, key, before, action.value})
+			continue
+		}
+
+		sec.Set(key, action.values[0])
+		changes = append(changes, change{AuditWrite, path, section, key, before, action.values[0]})
+		for _, value := range action.values[1:] {
+			sec.Add(key, value)
+			changes = append(changes, change{AuditAppend, path, section, key, "", value})
+		}
+	}
+
+	encoded := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		data, err := conf.encodeDocument(docs[path], path)
+		if err != nil {
+			return errors.New(fmt.Sprint(path, " : ", err))
+		}
+		encoded[path] = data
+	}
+
+	for _, path := range paths {
+		if _, direrr := conf.stat(filepath.Dir(path)); direrr != nil {
+			conf.storage().MkdirAll(filepath.Dir(path), conf.dirMode())
+		}
+		if err := conf.storeDocument(path, encoded[path]); err != nil {
+			return errors.New(fmt.Sprint(path, " : cannot save configuration ", err))
+		}
+	}
+
+	for _, change := range changes {
+		if err := conf.audit(change.operation, change.path, change.section, change.key, change.before, change.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localValue 함수는 section에 직접 기록된 key의 value를 반환합니다.
+// 상속된 key일 경우 false를 반환합니다.
+func localValue(tempsec section, key string) (string, bool) {
+	if _, inherited := tempsec.inherited[key]; inherited {
+		return "", false
+	}
+	value, ok := tempsec.data[key]
+	return value, ok
+}
+
+// localValues 함수는 section에 직접 기록된 key의 모든 value를 기록된 순서대로 반환합니다.
+// 상속된 key일 경우 false를 반환합니다.
+func localValues(tempsec section, key string) ([]string, bool) {
+	value, ok := localValue(tempsec, key)
+	if !ok {
+		return nil, false
+	}
+	if values, repeated := tempsec.values[key]; repeated {
+		return values, true
+	}
+	return []string{value}, true
+}
+
+// equalValues 함수는 두 value 목록이 같은 순서로 같은 value를 가지는지 확인합니다.
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sectionNames 함수는 여러 config의 모든 section 이름을 정렬하여 반환합니다.
+func sectionNames(confs ...*Configuration) []string {
+	names := map[string]bool{}
+	for _, conf := range confs {
+		for name := range conf.sections {
+			names[name] = true
+		}
+	}
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// keyNames 함수는 section의 모든 key를 정렬하여 반환합니다.
+func keyNames(tempsec section) []string {
+	var sorted []string
+	for key := range tempsec.data {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// orderedSections 함수는 모든 section 이름을 처음 병합된 순서대로 반환합니다.
+// 순서가 기록되지 않은 section은 이름 순서로 마지막에 위치합니다.
+func (conf *Configuration) orderedSections() []string {
+	return ordered(conf.order, sectionNames(conf), func(name string) bool {
+		_, ok := conf.sections[name]
+		return ok
+	})
+}
+
+// orderedKeys 함수는 section의 모든 key를 처음 병합된 순서대로 반환합니다.
+// 순서가 기록되지 않은 key는 이름 순서로 마지막에 위치합니다.
+func orderedKeys(tempsec section) []string {
+	return ordered(tempsec.order, keyNames(tempsec), func(key string) bool {
+		_, ok := tempsec.data[key]
+		return ok
+	})
+}
+
+// ordered 함수는 order에 기록된 이름 중 존재하는 이름을 먼저, 나머지 sorted의 이름을 이후에 반환합니다.
+func ordered(order, sorted []string, exist func(string) bool) []string {
+	seen := make(map[string]bool, len(sorted))
+	list := make([]string, 0, len(sorted))
+	for _, names := range [][]string{order, sorted} {
+		for _, name := range names {
+			if !seen[name] && exist(name) {
+				seen[name] = true
+				list = append(list, name)
+			}
+		}
+	}
+	return list
+}
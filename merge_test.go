@@ -0,0 +1,136 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMergeFunction(t *testing.T) {
+
+	/*
+		default.ini (dst)
+		; product default
+		[Server]
+		Port=80
+		Host=a
+
+		site.ini (src)
+		[Server]
+		Port=8080
+		Name=b
+
+		Merge(dst, src, MergePolicy{Strategy: MergeSrcWins})
+
+		--> [Server] Port=8080, Host=a, Name=b
+	*/
+
+	Convey("Merge Function", t, func() {
+		dir := t.TempDir()
+		dstpath := filepath.Join(dir, "default.ini")
+		writeTestFile(dstpath, "; product default\n[Server]\nPort=80\nHost=a\n[Database]\nUser=root\n")
+		writeTestFile(filepath.Join(dir, "site.ini"), "[Server]\nPort=8080\nName=b\n[Database]\nUser=admin\n")
+
+		dst := makeTestConfig(dstpath)
+		src := makeTestConfig(filepath.Join(dir, "site.ini"))
+
+		Convey("Merge Src Wins", func() {
+			conflicts, err := Merge(dst, src, MergePolicy{})
+			So(err, ShouldBeNil)
+			So(conflicts, ShouldHaveLength, 2)
+			So(conflicts[1], ShouldResemble, Conflict{Section: "Server", Key: "Port", Dst: "80", Src: "8080"})
+
+			So(dst.Find("Server", "Port"), ShouldEqual, "8080")
+			So(dst.Find("Server", "Host"), ShouldEqual, "a")
+			So(dst.Find("Server", "Name"), ShouldEqual, "b")
+
+			data, _ := os.ReadFile(dstpath)
+			So(string(data), ShouldStartWith, "; product default\n[Server]\n")
+		})
+
+		Convey("Merge Save Once", func() {
+			dst.SetBackupPolicy(BackupPolicy{Keep: 3})
+			dst.Write("Server", "Host", "b")
+			dst.Write("Server", "Host", "a")
+
+			_, err := Merge(dst, src, MergePolicy{})
+			So(err, ShouldBeNil)
+
+			list, _ := dst.ListBackups()
+			So(list, ShouldHaveLength, 3)
+			So(list[0].Time.After(list[1].Time), ShouldBeTrue)
+
+			data, _ := os.ReadFile(dstpath + ".bak")
+			So(string(data), ShouldEqual, "; product default\n[Server]\nPort=80\nHost=a\n[Database]\nUser=root\n")
+		})
+
+		Convey("Merge Section Rule", func() {
+			_, err := Merge(dst, src, MergePolicy{Strategy: MergeSrcWins, Sections: map[string]MergeStrategy{"Data*": MergeDstWins}})
+			So(err, ShouldBeNil)
+			So(dst.Find("Server", "Port"), ShouldEqual, "8080")
+			So(dst.Find("Database", "User"), ShouldEqual, "root")
+		})
+
+		Convey("Merge Error", func() {
+			conflicts, err := Merge(dst, src, MergePolicy{Strategy: MergeDstWins, Sections: map[string]MergeStrategy{"Server": MergeError}})
+			So(errors.Is(err, ErrMergeConflict), ShouldBeTrue)
+			So(conflicts, ShouldHaveLength, 2)
+			So(dst.Find("Server", "Name"), ShouldBeEmpty)
+		})
+
+		Convey("Merge Three Way", func() {
+			writeTestFile(filepath.Join(dir, "base.ini"), "[Server]\nPort=80\nHost=a\nMode=old\n[Database]\nUser=guest\n")
+			base := makeTestConfig(filepath.Join(dir, "base.ini"))
+
+			writeTestFile(filepath.Join(dir, "site.ini"), "[Server]\nPort=8080\nHost=a\n[Database]\nUser=admin\n")
+
+			conflicts, err := MergeThreeWay(base, dst, src, MergePolicy{Strategy: MergeError})
+			So(errors.Is(err, ErrMergeConflict), ShouldBeTrue)
+			So(conflicts, ShouldResemble, []Conflict{{Section: "Database", Key: "User", Base: "guest", Dst: "root", Src: "admin"}})
+
+			conflicts, err = MergeThreeWay(base, dst, src, MergePolicy{Strategy: MergeDstWins})
+			So(err, ShouldBeNil)
+			So(conflicts, ShouldHaveLength, 1)
+
+			So(dst.Find("Server", "Port"), ShouldEqual, "8080")
+			So(dst.Find("Database", "User"), ShouldEqual, "root")
+			_, verr := dst.ExistValue("Server", "Mode")
+			So(verr, ShouldNotBeNil)
+		})
+
+		Convey("Merge Repeated", func() {
+			writeTestFile(dstpath, "[Service]\nExec=1\nExec=2\nUser=app\n")
+			writeTestFile(filepath.Join(dir, "site.ini"), "[Service]\nExec=3\nExec=4\nUser=app\n")
+
+			conflicts, err := Merge(dst, src, MergePolicy{})
+			So(err, ShouldBeNil)
+			So(conflicts, ShouldResemble, []Conflict{{Section: "Service", Key: "Exec", Dst: "1, 2", Src: "3, 4"}})
+			So(dst.FindAll("Service", "Exec"), ShouldResemble, []string{"3", "4"})
+
+			data, _ := os.ReadFile(dstpath)
+			So(string(data), ShouldEqual, "[Service]\nExec=3\nExec=4\nUser=app\n")
+
+			conflicts, _ = Merge(dst, src, MergePolicy{})
+			So(conflicts, ShouldBeEmpty)
+		})
+
+		Convey("Merge Three Way Inherited", func() {
+			writeTestFile(filepath.Join(dir, "base.ini"), "[base]\nport=80\n[prod : base]\nhost=p\n")
+			writeTestFile(dstpath, "[base]\nport=80\n[prod : base]\nhost=p\n")
+			writeTestFile(filepath.Join(dir, "site.ini"), "[base]\nport=81\n[prod : base]\nhost=p\n")
+			base := makeTestConfig(filepath.Join(dir, "base.ini"))
+
+			_, err := MergeThreeWay(base, dst, src, MergePolicy{Strategy: MergeError})
+			So(err, ShouldBeNil)
+
+			data, _ := os.ReadFile(dstpath)
+			So(string(data), ShouldEqual, "[base]\nport=81\n[prod : base]\nhost=p\n")
+			from, inherited := dst.InheritedFrom("prod", "port")
+			So(from, ShouldEqual, "base")
+			So(inherited, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,329 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ListStyle 구조체는 하나의 value를 목록으로 나누는 방식입니다.
+// =======================================
+//
+// Separators	: 항목을 구분하는 문자 목록입니다. 공백일 경우 쉼표(,)를 사용합니다.
+// Quotes	: 항목을 감싸는 따옴표 문자 목록입니다. 항목의 첫 문자일 경우에만 따옴표로 간주합니다.
+//
+// 따옴표 안의 구분자는 항목의 일부이며, \는 다음 문자를 그대로 사용합니다.
+//
+// DefaultListStyle	: a, "b, c", d	--> [a] [b, c] [d]
+// WhitespaceListStyle	: a "b c" d	--> [a] [b c] [d]	(systemd unit 형식)
+//
+// 따옴표로 감싸지 않은 항목의 앞뒤 공백은 제거되며, 비어있는 항목은 무시됩니다.
+//
+// =======================================
+type ListStyle struct {
+	Separators string
+	Quotes     string
+}
+
+// 기본 제공 ListStyle 입니다.
+var (
+	DefaultListStyle    = ListStyle{Separators: ",", Quotes: `"'`}
+	WhitespaceListStyle = ListStyle{Separators: " \t", Quotes: `"'`}
+)
+
+// SetListStyle 함수는 FindList 등에서 value를 목록으로 나누는 방식을 지정합니다.
+func (conf *Configuration) SetListStyle(style ListStyle) {
+	conf.liststyle = &style
+}
+
+// FindAll 함수는 지정된 section과 key의 모든 value를 기록된 순서대로 반환합니다.
+// key가 반복되지 않은 경우 하나의 value를 반환하며, 존재하지 않을 경우 nil을 반환합니다.
+// secret value는 Find와 같이 복호화하며, 복호화할 수 없을 경우 공백값을 반환합니다.
+// =======================================
+//
+// [Service]
+// ExecStartPre=/bin/mkdir -p /run/app
+// ExecStartPre=/bin/chown app /run/app
+//
+// FindAll("Service", "ExecStartPre")	--> [/bin/mkdir -p /run/app, /bin/chown app /run/app]
+//
+// =======================================
+func (conf *Configuration) FindAll(section, key string) []string {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	section, key = conf.resolve(section, key)
+	targetsection, ok := conf.sections[section]
+	if !ok {
+		return nil
+	}
+
+	values, repeated := targetsection.values[key]
+	if !repeated {
+		value, exist := targetsection.data[key]
+		if !exist {
+			return nil
+		}
+		values = []string{value}
+	}
+
+	ret := make([]string, len(values))
+	for i, value := range values {
+		ret[i], _ = conf.reveal(value)
+	}
+	return ret
+}
+
+// Append 함수는 지정된 key에 value를 추가합니다.
+// 반복된 key를 허용하는 Format(INI)은 마지막 key 다음 줄에 같은 key를 추가하며,
+// 그 외의 Format은 기존 value 뒤에 ListStyle의 구분자로 연결하여 기록합니다.
+// key가 존재하지 않을 경우 Write와 같습니다.
+func (conf *Configuration) Append(section, key, value string) error {
+	conf.Read()
+	conf.mu.Lock()
+
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+	}()
+
+	return conf.append(section, key, value)
+}
+
+// append 함수는 Append의 내부 함수로, mutex를 사용하지 않고 value를 추가합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) append(section, key, value string) error {
+	if conf.confpath == "" {
+		return errors.New("Append : missing configuration path")
+	}
+	section, key = conf.resolve(section, key)
+	current, exist := conf.sections[section].data[key]
+	if !exist {
+		return conf.write(section, key, value)
+	}
+	if value == "" {
+		return errors.New("Append : missing value")
+	}
+
+	path := conf.target(section, key)
+
+	doc, derr := conf.readDocument(path)
+	if derr != nil {
+		return errors.New(fmt.Sprint("Append : cannot read configuration ", derr))
+	}
+
+	sec := doc.AddSection(conf.docSection(section, key))
+	if format, ok := conf.formatOf(path).(RepeatedFormat); ok && format.AllowRepeated() {
+		sec.Add(key, value)
+	} else if previous := sec.Values(key); len(previous) != 0 {
+		sec.Set(key, previous[len(previous)-1]+conf.listStyle().separator()+conf.listStyle().quote(value))
+	} else {
+		sec.Set(key, value)
+	}
+
+	if serr := conf.saveDocument(doc, path); serr != nil {
+		return serr
+	}
+	return conf.audit(AuditAppend, path, section, key, current, value)
+}
+
+// FindList 함수는 지정된 section과 key의 value를 ListStyle에 따라 목록으로 나누어 반환합니다.
+// key가 반복된 경우 모든 value의 항목을 순서대로 연결합니다.
+// value가 존재하지 않거나 따옴표가 닫히지 않은 경우 에러를 반환합니다.
+func (conf *Configuration) FindList(section, key string) ([]string, error) {
+	values := conf.FindAll(section, key)
+	if values == nil {
+		return nil, errors.New("FindList : cannot find value")
+	}
+
+	var list []string
+	for _, value := range values {
+		items, err := SplitList(value, conf.listStyle())
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("FindList : ", joinSection(section, key), " ", err))
+		}
+		list = append(list, items...)
+	}
+	return list, nil
+}
+
+// FindIntList 함수는 FindList의 각 항목을 int로 변환하여 반환합니다.
+// 변환할 수 없는 항목이 있을 경우 에러를 반환합니다.
+func (conf *Configuration) FindIntList(section, key string) ([]int, error) {
+	list, err := conf.FindList(section, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]int, len(list))
+	for i, item := range list {
+		if ret[i], err = strconv.Atoi(item); err != nil {
+			return nil, errors.New(fmt.Sprint("FindIntList : invalid value ", strconv.Quote(conf.redact(section, key, item))))
+		}
+	}
+	return ret, nil
+}
+
+// FindFloatList 함수는 FindList의 각 항목을 float64로 변환하여 반환합니다.
+// 변환할 수 없는 항목이 있을 경우 에러를 반환합니다.
+func (conf *Configuration) FindFloatList(section, key string) ([]float64, error) {
+	list, err := conf.FindList(section, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]float64, len(list))
+	for i, item := range list {
+		if ret[i], err = strconv.ParseFloat(item, 64); err != nil {
+			return nil, errors.New(fmt.Sprint("FindFloatList : invalid value ", strconv.Quote(conf.redact(section, key, item))))
+		}
+	}
+	return ret, nil
+}
+
+// FindDurationList 함수는 FindList의 각 항목을 time.Duration으로 변환하여 반환합니다.
+// 변환할 수 없는 항목이 있을 경우 에러를 반환합니다.
+func (conf *Configuration) FindDurationList(section, key string) ([]time.Duration, error) {
+	list, err := conf.FindList(section, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]time.Duration, len(list))
+	for i, item := range list {
+		if ret[i], err = time.ParseDuration(item); err != nil {
+			return nil, errors.New(fmt.Sprint("FindDurationList : invalid value ", strconv.Quote(conf.redact(section, key, item))))
+		}
+	}
+	return ret, nil
+}
+
+// SplitList 함수는 value를 style에 따라 목록으로 나눕니다.
+// 따옴표가 닫히지 않은 경우 에러를 반환합니다.
+func SplitList(value string, style ListStyle) ([]string, error) {
+	style = style.normalize()
+
+	var list []string
+	var item, pending strings.Builder
+	var quote rune
+	quoted := false
+
+	flush := func() {
+		if item.Len() != 0 || quoted {
+			list = append(list, item.String())
+		}
+		item.Reset()
+		pending.Reset()
+		quoted = false
+	}
+
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		switch {
+		case quote != 0 && char == '\\' && i+1 < len(runes):
+			i++
+			item.WriteRune(runes[i])
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+			item.WriteRune(char)
+		case strings.ContainsRune(style.Separators, char):
+			flush()
+		case unicode.IsSpace(char):
+			// 항목 사이의 공백은 뒤에 다른 문자가 올 경우에만 항목에 포함됩니다.
+			if item.Len() != 0 || quoted {
+				pending.WriteRune(char)
+			}
+		case item.Len() == 0 && !quoted && strings.ContainsRune(style.Quotes, char):
+			quote, quoted = char, true
+		default:
+			item.WriteString(pending.String())
+			pending.Reset()
+			item.WriteRune(char)
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	flush()
+	return list, nil
+}
+
+// JoinList 함수는 목록을 style의 첫번째 구분자로 연결합니다.
+// 구분자, 따옴표, 앞뒤 공백을 포함하거나 비어있는 항목은 따옴표로 감싸므로 SplitList로 같은 목록을 얻을 수 있습니다.
+func JoinList(list []string, style ListStyle) string {
+	style = style.normalize()
+
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = style.quote(item)
+	}
+	return strings.Join(items, style.separator())
+}
+
+// listStyle 함수는 Configuration에 지정된 ListStyle을 반환하며, 지정되지 않았을 경우 DefaultListStyle을 반환합니다.
+func (conf *Configuration) listStyle() ListStyle {
+	if conf.liststyle == nil {
+		return DefaultListStyle
+	}
+	return conf.liststyle.normalize()
+}
+
+// normalize 함수는 구분자가 지정되지 않은 ListStyle에 쉼표(,)를 사용합니다.
+func (style ListStyle) normalize() ListStyle {
+	if style.Separators == "" {
+		style.Separators = DefaultListStyle.Separators
+	}
+	return style
+}
+
+// separator 함수는 항목을 연결할 때 사용할 구분자를 반환합니다.
+// 공백이 아닌 구분자는 읽기 쉽도록 뒤에 공백을 붙입니다. (예: ", ")
+func (style ListStyle) separator() string {
+	separator := []rune(style.Separators)[0]
+	if unicode.IsSpace(separator) {
+		return string(separator)
+	}
+	return string(separator) + " "
+}
+
+// quote 함수는 SplitList로 나눌 때 그대로 복원되도록 필요한 경우 항목을 따옴표로 감쌉니다.
+// 따옴표가 지정되지 않은 ListStyle은 항목을 그대로 반환합니다.
+func (style ListStyle) quote(item string) string {
+	if style.Quotes == "" {
+		return item
+	}
+	if item != "" && strings.TrimSpace(item) == item && !strings.ContainsAny(item, style.Separators+style.Quotes) {
+		return item
+	}
+
+	quote := string([]rune(style.Quotes)[0])
+	escaped := strings.NewReplacer(`\`, `\\`, quote, `\`+quote).Replace(item)
+	return quote + escaped + quote
+}
+
+// setValues 함수는 반복된 key의 모든 value를 section에 저장합니다.
+// value가 하나 이하일 경우 반복되지 않은 key로 간주하여 삭제합니다.
+func setValues(targetsection *section, key string, values []string) {
+	if len(values) < 2 {
+		delete(targetsection.values, key)
+		return
+	}
+	if targetsection.values == nil {
+		targetsection.values = map[string][]string{}
+	}
+	targetsection.values[key] = append([]string{}, values...)
+}
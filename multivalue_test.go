@@ -0,0 +1,123 @@
+package conf4g
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMultiValueFunction(t *testing.T) {
+
+	/*
+		[Service]
+		ExecStartPre=/bin/mkdir -p /run/app
+		ExecStartPre=/bin/chown app /run/app
+
+		variable.FindAll("Service", "ExecStartPre")	--> [/bin/mkdir -p /run/app, /bin/chown app /run/app]
+		variable.FindList("cluster", "servers")		--> [a, b c, d]
+	*/
+
+	Convey("MultiValue Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "[Service]\nExecStartPre=/bin/mkdir -p /run/app\nExecStartPre=/bin/chown app /run/app\nType=simple\n[cluster]\nservers=a, \"b c\", d\nports=80, 443\ntimeouts=1s,2m\nweights=0.5, 1.5\nbroken=\"a, b\n")
+
+		Convey("FindAll", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.FindAll("Service", "ExecStartPre"), ShouldResemble, []string{"/bin/mkdir -p /run/app", "/bin/chown app /run/app"})
+			So(conf.FindAll("Service", "Type"), ShouldResemble, []string{"simple"})
+			So(conf.FindAll("Service", "none"), ShouldBeNil)
+			So(conf.Find("Service", "ExecStartPre"), ShouldEqual, "/bin/chown app /run/app")
+		})
+
+		Convey("FindList", func() {
+			conf, _ := New(WithPath(path))
+			list, err := conf.FindList("cluster", "servers")
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, []string{"a", "b c", "d"})
+
+			ints, err := conf.FindIntList("cluster", "ports")
+			So(err, ShouldBeNil)
+			So(ints, ShouldResemble, []int{80, 443})
+
+			durations, err := conf.FindDurationList("cluster", "timeouts")
+			So(err, ShouldBeNil)
+			So(durations, ShouldResemble, []time.Duration{time.Second, 2 * time.Minute})
+
+			floats, err := conf.FindFloatList("cluster", "weights")
+			So(err, ShouldBeNil)
+			So(floats, ShouldResemble, []float64{0.5, 1.5})
+
+			_, err = conf.FindIntList("cluster", "servers")
+			So(err, ShouldNotBeNil)
+			_, err = conf.FindList("cluster", "broken")
+			So(err, ShouldNotBeNil)
+			_, err = conf.FindList("cluster", "none")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("FindList Whitespace", func() {
+			conf, _ := New(WithPath(path), WithListStyle(WhitespaceListStyle))
+			list, err := conf.FindList("Service", "ExecStartPre")
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, []string{"/bin/mkdir", "-p", "/run/app", "/bin/chown", "app", "/run/app"})
+		})
+
+		Convey("Append INI", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.Append("Service", "ExecStartPre", "/bin/true"), ShouldBeNil)
+			So(conf.FindAll("Service", "ExecStartPre"), ShouldHaveLength, 3)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "ExecStartPre=/bin/chown app /run/app\nExecStartPre=/bin/true\nType=simple\n")
+
+			So(conf.Append("Service", "User", "app"), ShouldBeNil)
+			So(conf.FindAll("Service", "User"), ShouldResemble, []string{"app"})
+		})
+
+		Convey("Append JSON", func() {
+			jsonpath := filepath.Join(dir, "app.json")
+			writeTestFile(jsonpath, `{"cluster": {"servers": "a"}}`)
+
+			conf, _ := New(WithPath(jsonpath))
+			So(conf.Append("cluster", "servers", "b c"), ShouldBeNil)
+			So(conf.Find("cluster", "servers"), ShouldEqual, "a, b c")
+
+			list, err := conf.FindList("cluster", "servers")
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, []string{"a", "b c"})
+		})
+
+		Convey("Write And Delete Repeated", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.Write("Service", "ExecStartPre", "/bin/false"), ShouldBeNil)
+			So(conf.FindAll("Service", "ExecStartPre"), ShouldResemble, []string{"/bin/false"})
+
+			So(conf.Append("Service", "ExecStartPre", "/bin/true"), ShouldBeNil)
+			So(conf.DeleteValue("Service", "ExecStartPre"), ShouldBeNil)
+			So(conf.FindAll("Service", "ExecStartPre"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldNotContainSubstring, "ExecStartPre")
+		})
+
+		Convey("SplitList And JoinList", func() {
+			list := []string{"a", "b, c", " d ", "", `e"f`}
+			for _, style := range []ListStyle{DefaultListStyle, WhitespaceListStyle} {
+				ret, err := SplitList(JoinList(list, style), style)
+				So(err, ShouldBeNil)
+				So(ret, ShouldResemble, list)
+			}
+
+			ret, err := SplitList(`a, it's, 'b,c'`, DefaultListStyle)
+			So(err, ShouldBeNil)
+			So(ret, ShouldResemble, []string{"a", "it's", "b,c"})
+
+			_, err = SplitList(`a, "b`, DefaultListStyle)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,293 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 신규로 생성하는 config 파일과 폴더의 기본 권한입니다.
+// config 파일에는 비밀번호 등이 기록될 수 있으므로 소유자만 접근할 수 있도록 합니다.
+const (
+	defaultFileMode os.FileMode = 0600
+	defaultDirMode  os.FileMode = 0700
+)
+
+// Logger 인터페이스는 conf4g의 경고와 진단 메시지를 출력하는 대상입니다.
+// *log.Logger를 그대로 사용할 수 있습니다.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Option 타입은 New 함수에 전달하는 설정입니다.
+type Option func(*options)
+
+// options 구조체는 New 함수에 전달된 설정을 모읍니다.
+type options struct {
+	path      string
+	basedir   string
+	format    Format
+	filemode  os.FileMode
+	dirmode   os.FileMode
+	storage   Storage
+	envprefix string
+	liststyle *ListStyle
+	encoding  *Encoding
+	match     NameMatch
+	profile   string
+	strict    bool
+	logger    Logger
+}
+
+// WithPath 함수는 config 파일의 경로를 지정합니다.
+// 상대 경로는 WithBaseDir로 지정된 폴더를 기준으로 합니다.
+// 지정하지 않을 경우 {base}/config/{실행 파일 이름}.ini를 사용합니다.
+func WithPath(path string) Option {
+	return func(o *options) { o.path = path }
+}
+
+// WithBaseDir 함수는 상대 경로의 기준 폴더를 지정합니다.
+// 지정하지 않을 경우 실행 파일의 폴더를 사용하며, go run/go test로 실행된 경우 현재 작업 폴더를 사용합니다.
+func WithBaseDir(dir string) Option {
+	return func(o *options) { o.basedir = dir }
+}
+
+// WithFormat 함수는 config 파일의 Format을 지정합니다. SetFormat과 같습니다.
+func WithFormat(format Format) Option {
+	return func(o *options) { o.format = format }
+}
+
+// WithFileMode 함수는 신규로 생성하는 config 파일의 권한을 지정합니다.
+// 지정하지 않을 경우 0600을 사용하며, 기존 파일의 권한과 소유자는 변경하지 않습니다.
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *options) { o.filemode = mode }
+}
+
+// WithDirMode 함수는 config 파일을 기록할 때 신규로 생성하는 폴더의 권한을 지정합니다.
+// 지정하지 않을 경우 0700을 사용합니다.
+func WithDirMode(mode os.FileMode) Option {
+	return func(o *options) { o.dirmode = mode }
+}
+
+// WithStorage 함수는 config 파일을 읽고 쓸 Storage를 지정합니다.
+func WithStorage(storage Storage) Option {
+	return func(o *options) { o.storage = storage }
+}
+
+// WithEnvPrefix 함수는 value를 덮어쓸 환경변수의 접두어를 지정합니다.
+// 환경변수 이름은 {prefix}_{SECTION}_{KEY} 형식의 대문자이며, 점(.)과 하이픈(-)은 밑줄(_)로 변환됩니다.
+// 환경변수는 config 파일에 존재하는 key에만 적용되며, 파일에는 기록되지 않습니다.
+// =======================================
+//
+// prefix : APP
+//
+// [server.web]		-->	APP_SERVER_WEB_PORT=8080
+// port=80
+//
+// =======================================
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) { o.envprefix = prefix }
+}
+
+// WithListStyle 함수는 value를 목록으로 나누는 방식을 지정합니다. SetListStyle과 같습니다.
+func WithListStyle(style ListStyle) Option {
+	return func(o *options) { o.liststyle = &style }
+}
+
+// WithEncoding 함수는 BOM이 없는 config 파일의 인코딩을 지정합니다. SetEncoding과 같습니다.
+// =======================================
+//
+// conf, err := conf4g.New(conf4g.WithPath("legacy.ini"), conf4g.WithEncoding(conf4g.CP949))
+//
+// =======================================
+func WithEncoding(enc Encoding) Option {
+	return func(o *options) { o.encoding = &enc }
+}
+
+// WithNameMatch 함수는 section과 key 이름의 비교 방식을 지정합니다. SetNameMatch와 같습니다.
+// 한 파일에 같은 이름으로 간주되는 section 또는 key가 서로 다르게 기록되어 있을 경우 Read가 ErrNameCollision을 반환합니다.
+func WithNameMatch(match NameMatch) Option {
+	return func(o *options) { o.match = match }
+}
+
+// WithProfile 함수는 기본 profile을 지정합니다. SetProfile과 같습니다.
+// {prefix}_PROFILE 환경변수가 있을 경우 환경변수의 profile을 사용합니다.
+func WithProfile(profile string) Option {
+	return func(o *options) { o.profile = profile }
+}
+
+// WithStrict 함수는 엄격 모드를 사용합니다.
+// 엄격 모드에서는 config 파일이 존재하지 않거나, 한 파일의 같은 section에 key가 중복될 경우 Read가 에러를 반환합니다.
+// 민감한 key가 기록된 파일을 그룹 또는 다른 사용자가 읽을 수 있을 경우에도 경고 대신 ErrInsecurePermission을 반환합니다.
+func WithStrict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// WithLogger 함수는 경고와 진단 메시지를 출력할 Logger를 지정합니다.
+// 지정하지 않을 경우 메시지를 출력하지 않습니다.
+func WithLogger(logger Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// New 함수는 Option을 적용한 Configuration을 반환합니다.
+// Option을 지정하지 않을 경우 MakeConfig 후 Initialize를 호출한 것과 같습니다.
+// =======================================
+//
+// conf, err := conf4g.New(conf4g.WithPath("/etc/app/app.yaml"), conf4g.WithEnvPrefix("APP"))
+//
+// =======================================
+func New(opts ...Option) (*Configuration, error) {
+	conf := MakeConfig()
+	if err := conf.configure(opts...); err != nil {
+		return nil, errors.New(fmt.Sprint("New : ", err))
+	}
+	return conf, nil
+}
+
+// configure 함수는 Option을 적용하고 config 파일의 경로를 결정합니다.
+// 지정되지 않은 Option은 기존 값을 유지합니다.
+func (conf *Configuration) configure(opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		if opt == nil {
+			return errors.New("nil option")
+		}
+		opt(&o)
+	}
+
+	if o.filemode&^os.ModePerm != 0 {
+		return errors.New(fmt.Sprint("invalid file mode ", o.filemode))
+	}
+	if o.dirmode&^os.ModePerm != 0 {
+		return errors.New(fmt.Sprint("invalid directory mode ", o.dirmode))
+	}
+
+	basedir := o.basedir
+	if basedir == "" {
+		basedir = defaultBaseDir()
+	}
+
+	if o.path == "" {
+		base := filepath.Base(os.Args[0])
+
+		if strings.Contains(base, ".") {
+			bound := strings.Split(base, ".")
+			base = bound[0] + ".ini"
+		}
+
+		conf.confpath = filepath.Join(basedir, "config/", base)
+	} else {
+		conf.confpath = resolvePath(basedir, filepath.Clean(o.path))
+	}
+
+	if conf.sections == nil {
+		conf.sections = make(map[string]section)
+	}
+	if o.format != nil {
+		conf.format = o.format
+	}
+	if o.filemode != 0 {
+		conf.filemode = o.filemode
+	}
+	if o.dirmode != 0 {
+		conf.dirmode = o.dirmode
+	}
+	if o.storage != nil {
+		conf.store = o.storage
+	}
+	if o.envprefix != "" {
+		conf.envprefix = o.envprefix
+	}
+	if o.liststyle != nil {
+		conf.liststyle = o.liststyle
+	}
+	if o.encoding != nil {
+		conf.encoding = o.encoding
+	}
+	if o.match != MatchExact {
+		conf.match = o.match
+	}
+	if o.profile != "" {
+		conf.profile = o.profile
+	}
+	if o.strict {
+		conf.strict = true
+	}
+	if o.logger != nil {
+		conf.logger = o.logger
+	}
+	return nil
+}
+
+// defaultBaseDir 함수는 실행 파일의 폴더를 반환합니다.
+// go run, go test 등으로 임시 폴더에서 실행된 경우 현재 작업 폴더를 반환합니다.
+func defaultBaseDir() string {
+	target, _ := filepath.Abs(filepath.Dir(os.Args[0]))
+
+	if strings.Contains(target, "go-build") {
+		target, _ = os.Getwd()
+	}
+	return target
+}
+
+// mode 함수는 신규로 생성하는 파일의 권한을 반환합니다.
+func (conf *Configuration) mode() os.FileMode {
+	if conf.filemode == 0 {
+		return defaultFileMode
+	}
+	return conf.filemode
+}
+
+// dirMode 함수는 신규로 생성하는 폴더의 권한을 반환합니다.
+func (conf *Configuration) dirMode() os.FileMode {
+	if conf.dirmode == 0 {
+		return defaultDirMode
+	}
+	return conf.dirmode
+}
+
+// warnf 함수는 Logger가 지정된 경우 경고 메시지를 출력합니다.
+// Read는 자주 호출되므로 같은 메시지는 한번만 출력합니다.
+func (conf *Configuration) warnf(format string, v ...interface{}) {
+	if conf.logger == nil {
+		return
+	}
+
+	message := fmt.Sprintf(format, v...)
+	if conf.warned[message] {
+		return
+	}
+	if conf.warned == nil {
+		conf.warned = map[string]bool{}
+	}
+	conf.warned[message] = true
+	conf.logger.Printf("%s", message)
+}
+
+// overrideEnv 함수는 접두어가 지정된 경우 환경변수의 값으로 value를 덮어씁니다.
+func (conf *Configuration) overrideEnv() {
+	if conf.envprefix == "" {
+		return
+	}
+
+	for name, targetsection := range conf.sections {
+		for key := range targetsection.data {
+			variable := envKey(conf.envprefix + "_" + key)
+			if name != "" {
+				variable = envKey(conf.envprefix + "_" + name + "_" + key)
+			}
+
+			if value, ok := os.LookupEnv(variable); ok {
+				targetsection.data[key] = value
+				delete(targetsection.values, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package conf4g
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOptionFunction(t *testing.T) {
+
+	/*
+		variable, err := New(WithPath("app.ini"), WithBaseDir("/etc/app"), WithEnvPrefix("APP"))
+
+		--> /etc/app/app.ini
+	*/
+
+	Convey("Option Function", t, func() {
+		dir := t.TempDir()
+
+		Convey("Option Path", func() {
+			conf, err := New(WithBaseDir(dir), WithPath("app.ini"), WithFormat(JSON))
+			So(err, ShouldBeNil)
+
+			path, _ := conf.GetCurrentPath()
+			So(path, ShouldEqual, filepath.Join(dir, "app.ini"))
+			So(conf.GetFormat().Name(), ShouldEqual, "json")
+
+			_, err = New(WithFileMode(os.ModeDir | 0700))
+			So(err, ShouldNotBeNil)
+			_, err = New(nil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Option Zero Value", func() {
+			conf := MakeConfig()
+			So(conf.Read(), ShouldNotBeNil)
+			So(conf.Write("Section001", "Key001", "Value001"), ShouldBeError, "Write : missing configuration path")
+			So(conf.Append("Section001", "Key001", "Value001"), ShouldBeError, "Append : missing configuration path")
+			So(conf.DeleteValue("Section001", "Key001"), ShouldBeError, "DeleteValue : missing configuration path")
+			So(conf.DeleteSection("Section001"), ShouldBeError, "DeleteSection : missing configuration path")
+			So(conf.Find("Section001", "Key001"), ShouldBeEmpty)
+
+			var zero Configuration
+			So(zero.Write("Section001", "Key001", "Value001"), ShouldBeError, "Write : missing configuration path")
+		})
+
+		Convey("Option File Mode", func() {
+			conf, _ := New(WithPath(filepath.Join(dir, "app.ini")), WithFileMode(0640))
+			So(conf.Write("Section001", "Key001", "Value001"), ShouldBeNil)
+
+			info, err := os.Stat(filepath.Join(dir, "app.ini"))
+			So(err, ShouldBeNil)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0640))
+		})
+
+		Convey("Option Storage", func() {
+			storage := NewMemoryStorage()
+			conf, _ := New(WithPath("/memory/app.ini"), WithStorage(storage))
+
+			So(conf.Write("Section001", "Key001", "Value001"), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+
+			data, err := storage.ReadFile("/memory/app.ini")
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, "[Section001]\nKey001=Value001\n")
+
+			_, serr := os.Stat("/memory/app.ini")
+			So(os.IsNotExist(serr), ShouldBeTrue)
+		})
+
+		Convey("Option Env Prefix", func() {
+			path := filepath.Join(dir, "app.ini")
+			writeTestFile(path, "Debug=false\n[server.web]\nport=80\n")
+			t.Setenv("APP_SERVER_WEB_PORT", "8080")
+			t.Setenv("APP_DEBUG", "true")
+
+			conf, _ := New(WithPath(path), WithEnvPrefix("APP"))
+			So(conf.Find("server.web", "port"), ShouldEqual, "8080")
+			So(conf.Find("", "Debug"), ShouldEqual, "true")
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "port=80\n")
+		})
+
+		Convey("Option Strict", func() {
+			path := filepath.Join(dir, "app.ini")
+
+			conf, _ := New(WithPath(path), WithStrict())
+			So(conf.Read(), ShouldNotBeNil)
+
+			writeTestFile(path, "[Section001]\nKey001=Value001\nKey001=Value002\n")
+			So(conf.Read(), ShouldNotBeNil)
+
+			loose, _ := New(WithPath(path))
+			So(loose.Read(), ShouldBeNil)
+			So(loose.Find("Section001", "Key001"), ShouldEqual, "Value002")
+		})
+
+		Convey("Option Logger", func() {
+			path := filepath.Join(dir, "app.ini")
+			writeTestFile(path, "include=-missing.ini\n[Section001]\nKey001=Value001\n")
+
+			var buffer bytes.Buffer
+			conf, _ := New(WithPath(path), WithLogger(log.New(&buffer, "", 0)))
+			conf.Read()
+			conf.Read()
+
+			So(buffer.String(), ShouldEqual, "conf4g: optional include "+filepath.Join(dir, "missing.ini")+" not found (included from "+path+")\n")
+		})
+	})
+}
@@ -0,0 +1,13 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+
+package conf4g
+
+import "os"
+
+// chownLike 함수는 소유자 개념이 다른 플랫폼에서는 아무것도 하지 않습니다.
+func chownLike(path string, info os.FileInfo) error { return nil }
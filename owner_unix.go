@@ -0,0 +1,31 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package conf4g
+
+import (
+	"os"
+	"syscall"
+)
+
+// chownLike 함수는 path의 소유자와 그룹을 info의 소유자와 그룹으로 변경합니다.
+// 이미 같을 경우 아무것도 하지 않으므로, 일반 사용자가 자신의 파일을 기록할 때는 권한이 필요하지 않습니다.
+func chownLike(path string, info os.FileInfo) error {
+	origin, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	current, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if target, tok := current.Sys().(*syscall.Stat_t); tok && target.Uid == origin.Uid && target.Gid == origin.Gid {
+		return nil
+	}
+	return os.Chown(path, int(origin.Uid), int(origin.Gid))
+}
@@ -0,0 +1,47 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// ErrInsecurePermission 에러는 민감한 key가 기록된 파일을 그룹 또는 다른 사용자가 읽을 수 있을 때 엄격 모드에서 반환됩니다.
+var ErrInsecurePermission = errors.New("permission : sensitive configuration is readable by group or others")
+
+// checkPermission 함수는 민감한 key가 기록된 파일의 권한을 확인합니다.
+// 그룹 또는 다른 사용자가 읽을 수 있을 경우 경고를 출력하며, 엄격 모드에서는 에러를 반환합니다.
+// Windows는 권한 비트를 사용하지 않으므로 확인하지 않습니다.
+func (conf *Configuration) checkPermission(path string, doc *Document) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := conf.storage().Stat(path)
+	if err != nil || info.Mode().Perm()&0044 == 0 {
+		return nil
+	}
+
+	for _, tempsec := range doc.Sections {
+		if tempsec.Name == includeSection {
+			continue
+		}
+		for _, entry := range tempsec.Entries {
+			if !hasSensitiveTag(entry.Comments) && !conf.isSensitive(tempsec.Name, entry.Key) {
+				continue
+			}
+
+			if conf.strict {
+				return fmt.Errorf("%w : %s (%v, %s)", ErrInsecurePermission, path, info.Mode().Perm(), joinSection(tempsec.Name, entry.Key))
+			}
+			conf.warnf("conf4g: %s contains sensitive key %s but is readable by group or others (%v), use chmod 600", path, joinSection(tempsec.Name, entry.Key), info.Mode().Perm())
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPermissionFunction(t *testing.T) {
+
+	/*
+		variable, err := New(WithPath("/etc/app/app.ini"), WithDirMode(0750), WithFileMode(0640))
+
+		--> /etc/app		drwxr-x---
+		--> /etc/app/app.ini	-rw-r-----
+	*/
+
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported")
+	}
+
+	Convey("Permission Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "nested", "app.ini")
+
+		Convey("Permission Default", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.Write("Section001", "Password", "p@ss"), ShouldBeNil)
+
+			info, _ := os.Stat(path)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+
+			parent, _ := os.Stat(filepath.Dir(path))
+			So(parent.Mode().Perm(), ShouldEqual, os.FileMode(0700))
+
+			backup, _ := os.Stat(path + ".bak")
+			So(backup.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+		})
+
+		Convey("Permission Option", func() {
+			conf, err := New(WithPath(path), WithDirMode(0750), WithFileMode(0640))
+			So(err, ShouldBeNil)
+			So(conf.Write("Section001", "Key001", "Value001"), ShouldBeNil)
+
+			parent, _ := os.Stat(filepath.Dir(path))
+			So(parent.Mode().Perm(), ShouldEqual, os.FileMode(0750))
+
+			_, err = New(WithDirMode(os.ModeDir | 0700))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Permission Preserve", func() {
+			writeTestFile(path, "[Section001]\nKey001=Value001\n")
+			os.Chmod(path, 0640)
+
+			conf, _ := New(WithPath(path))
+			So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+
+			info, _ := os.Stat(path)
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0640))
+		})
+
+		Convey("Permission Backup", func() {
+			writeTestFile(path, "[Section001]\npassword=old\n")
+			os.Chmod(path, 0600)
+			writeTestFile(path+".bak", "[Section001]\npassword=older\n")
+			os.Chmod(path+".bak", 0644)
+
+			conf, _ := New(WithPath(path))
+			So(conf.Write("Section001", "password", "new"), ShouldBeNil)
+
+			info, _ := os.Stat(path + ".bak")
+			So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+			data, _ := os.ReadFile(path + ".bak")
+			So(string(data), ShouldEqual, "[Section001]\npassword=old\n")
+		})
+
+		Convey("Permission Sensitive", func() {
+			writeTestFile(path, "[database]\nhost=localhost\npassword=p@ss\n")
+			os.Chmod(path, 0644)
+
+			var buffer bytes.Buffer
+			conf, _ := New(WithPath(path), WithLogger(log.New(&buffer, "", 0)))
+			So(conf.Read(), ShouldBeNil)
+			So(conf.Find("database", "password"), ShouldEqual, "p@ss")
+			So(buffer.String(), ShouldContainSubstring, "database.password")
+
+			strict, _ := New(WithPath(path), WithStrict())
+			So(errors.Is(strict.Read(), ErrInsecurePermission), ShouldBeTrue)
+
+			os.Chmod(path, 0600)
+			So(strict.Read(), ShouldBeNil)
+
+			plain := filepath.Join(dir, "plain.ini")
+			writeTestFile(plain, "[database]\nhost=localhost\n")
+			os.Chmod(plain, 0644)
+
+			relaxed, _ := New(WithPath(plain), WithStrict())
+			So(relaxed.Read(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// profile에 대한 설정값입니다.
+// =======================================
+//
+// profile이 prod일 경우 다음 value가 기본 value를 덮어씁니다.
+//
+// 1. {config 폴더}/profiles/prod.ini 파일의 value
+// 2. config 파일들의 [database@prod] section의 value	-->	[database]
+//
+// 두 곳에 같은 key가 있을 경우 [database@prod] section의 value가 우선합니다.
+// profile section은 그 자체로도 조회할 수 있습니다. (Find("database@prod", "host"))
+//
+// =======================================
+const (
+	profileDir       = "profiles"
+	profileSeparator = "@"
+	profileEnv       = "_PROFILE"
+	profileEnvPrefix = "APP"
+)
+
+// ProfileSource 구조체는 하나의 value가 어느 profile에서 적용되었는지에 대한 정보입니다.
+// Profile이 공백일 경우 기본 value입니다.
+type ProfileSource struct {
+	Section string
+	Key     string
+	Profile string
+	File    string
+}
+
+// SetProfile 함수는 기본 profile을 지정합니다.
+// {envprefix}_PROFILE 환경변수가 있을 경우 환경변수의 profile이 우선합니다.
+// envprefix가 지정되지 않았을 경우 APP_PROFILE 환경변수를 사용합니다.
+func (conf *Configuration) SetProfile(profile string) {
+	conf.profile = profile
+}
+
+// GetProfile 함수는 현재 적용되는 profile의 이름을 반환합니다.
+// {envprefix}_PROFILE 환경변수를 먼저 확인하며, envprefix가 지정되지 않았을 경우 APP_PROFILE 환경변수를 확인합니다.
+// 환경변수가 없거나 공백일 경우 SetProfile로 지정된 profile을 반환하며, 지정되지 않았을 경우 공백값을 반환합니다.
+func (conf *Configuration) GetProfile() string {
+	prefix := conf.envprefix
+	if prefix == "" {
+		prefix = profileEnvPrefix
+	}
+	if profile := strings.TrimSpace(os.Getenv(prefix + profileEnv)); profile != "" {
+		return profile
+	}
+	return conf.profile
+}
+
+// ProfileSources 함수는 모든 value가 적용된 profile과 파일을 section과 key 순서로 반환합니다.
+// profile section 자체는 포함하지 않습니다.
+// =======================================
+//
+// database.host	prod	/etc/app/app.ini		([database@prod])
+// database.port		/etc/app/app.ini		([database])
+// cache.size		prod	/etc/app/profiles/prod.ini
+//
+// =======================================
+func (conf *Configuration) ProfileSources() []ProfileSource {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	profile, overlay := conf.GetProfile(), conf.profilePath()
+
+	var list []ProfileSource
+	for _, name := range sectionNames(conf) {
+		if strings.Contains(name, profileSeparator) {
+			continue
+		}
+
+		targetsection := conf.sections[name]
+		for _, key := range keyNames(targetsection) {
+			source := ProfileSource{Section: name, Key: key, File: targetsection.origin[key]}
+			if _, ok := targetsection.profiled[key]; ok || source.File == overlay && overlay != "" {
+				source.Profile = profile
+			}
+			list = append(list, source)
+		}
+	}
+	return list
+}
+
+// profilePath 함수는 현재 profile의 파일 경로를 반환합니다.
+// profile이 지정되지 않았거나 경로로 사용할 수 없는 이름일 경우 공백값을 반환합니다.
+func (conf *Configuration) profilePath() string {
+	profile := conf.GetProfile()
+	if profile == "" || profile == "." || profile == ".." || strings.ContainsAny(profile, `/\`) {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(conf.confpath), profileDir, profile+filepath.Ext(conf.confpath))
+}
+
+// applyProfile 함수는 현재 profile의 파일과 profile section을 기본 section에 적용합니다.
+func (conf *Configuration) applyProfile() error {
+	profile := conf.GetProfile()
+	if profile == "" {
+		return nil
+	}
+
+	overlay := conf.profilePath()
+	if overlay == "" {
+		conf.warnf("conf4g: invalid profile name %q", profile)
+		return nil
+	}
+	if ftype, _ := conf.stat(overlay); ftype == 1 {
+		if err := conf.merge(overlay, nil); err != nil {
+			return err
+		}
+	}
+
+	var names []string
+	for name := range conf.sections {
+		if strings.HasSuffix(name, profileSeparator+profile) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		base := conf.sectionName(strings.TrimSuffix(name, profileSeparator+profile))
+		source := conf.sections[name]
+
+		targetsection, ok := conf.sections[base]
+		if !ok {
+			targetsection = section{
+				name:      base,
+				data:      map[string]string{},
+				origin:    map[string]string{},
+				sensitive: map[string]bool{},
+			}
+			conf.order = append(conf.order, base)
+		}
+		if targetsection.profiled == nil {
+			targetsection.profiled = map[string]string{}
+		}
+
+		for _, key := range orderedKeys(source) {
+			if _, exist := targetsection.data[key]; !exist {
+				targetsection.order = append(targetsection.order, key)
+			}
+			targetsection.data[key] = source.data[key]
+			targetsection.origin[key] = source.origin[key]
+			targetsection.profiled[key] = name
+			setValues(&targetsection, key, source.values[key])
+			if source.sensitive[key] {
+				targetsection.sensitive[key] = true
+			}
+		}
+		for _, file := range source.files {
+			targetsection.files = appendUnique(targetsection.files, file)
+		}
+		if source.parent != "" {
+			targetsection.parent = source.parent
+		}
+		conf.sections[base] = targetsection
+	}
+	return nil
+}
+
+// docSection 함수는 value를 기록할 파일 내의 section 이름을 반환합니다.
+// profile section에서 적용된 value는 해당 profile section에 기록합니다.
+func (conf *Configuration) docSection(section, key string) string {
+	if name, ok := conf.sections[section].profiled[key]; ok {
+		return name
+	}
+	return section
+}
@@ -0,0 +1,104 @@
+package conf4g
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfileFunction(t *testing.T) {
+
+	/*
+		[database]
+		host=localhost
+
+		[database@prod]
+		host=prod.internal
+
+		variable, _ := New(WithProfile("prod"))
+		variable.Find("database", "host")	--> prod.internal
+	*/
+
+	Convey("Profile Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		overlay := filepath.Join(dir, "profiles", "prod.ini")
+		writeTestFile(path, "[database]\nhost=localhost\nport=5432\n[database@prod]\nhost=prod.internal\n[database@dev]\nhost=dev.internal\n")
+		writeTestFile(overlay, "[cache]\nsize=512\n[database]\nhost=overlay.internal\nuser=app\n")
+		t.Setenv("APP_PROFILE", "")
+
+		Convey("Profile None", func() {
+			conf, _ := New(WithPath(path))
+			So(conf.GetProfile(), ShouldBeEmpty)
+			So(conf.Find("database", "host"), ShouldEqual, "localhost")
+			So(conf.Find("cache", "size"), ShouldBeEmpty)
+			So(conf.Find("database@prod", "host"), ShouldEqual, "prod.internal")
+		})
+
+		Convey("Profile Option", func() {
+			conf, _ := New(WithPath(path), WithProfile("prod"))
+			So(conf.GetProfile(), ShouldEqual, "prod")
+			So(conf.Find("database", "host"), ShouldEqual, "prod.internal")
+			So(conf.Find("database", "port"), ShouldEqual, "5432")
+			So(conf.Find("database", "user"), ShouldEqual, "app")
+			So(conf.Find("cache", "size"), ShouldEqual, "512")
+
+			conf.SetProfile("dev")
+			So(conf.Find("database", "host"), ShouldEqual, "dev.internal")
+			So(conf.Find("cache", "size"), ShouldBeEmpty)
+		})
+
+		Convey("Profile Env", func() {
+			t.Setenv("APP_PROFILE", "dev")
+			conf, _ := New(WithPath(path), WithProfile("prod"), WithEnvPrefix("APP"))
+			So(conf.GetProfile(), ShouldEqual, "dev")
+			So(conf.Find("database", "host"), ShouldEqual, "dev.internal")
+
+			t.Setenv("APP_PROFILE", "../secret")
+			So(conf.Find("database", "host"), ShouldEqual, "localhost")
+		})
+
+		Convey("Profile Env Default", func() {
+			t.Setenv("APP_PROFILE", "dev")
+			conf, _ := New(WithPath(path), WithProfile("prod"))
+			So(conf.GetProfile(), ShouldEqual, "dev")
+			So(conf.Find("database", "host"), ShouldEqual, "dev.internal")
+
+			t.Setenv("CONF_PROFILE", "prod")
+			prefixed, _ := New(WithPath(path), WithEnvPrefix("CONF"))
+			So(prefixed.GetProfile(), ShouldEqual, "prod")
+
+			t.Setenv("APP_PROFILE", "")
+			So(conf.GetProfile(), ShouldEqual, "prod")
+		})
+
+		Convey("Profile Write", func() {
+			conf, _ := New(WithPath(path), WithProfile("prod"))
+			So(conf.Write("database", "host", "db.prod.internal"), ShouldBeNil)
+			So(conf.Find("database", "host"), ShouldEqual, "db.prod.internal")
+			So(conf.Write("database", "user", "admin"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "[database]\nhost=localhost\n")
+			So(string(data), ShouldContainSubstring, "[database@prod]\nhost=db.prod.internal\n")
+
+			data, _ = os.ReadFile(overlay)
+			So(string(data), ShouldContainSubstring, "user=admin\n")
+
+			So(conf.DeleteValue("database", "host"), ShouldBeNil)
+			So(conf.Find("database", "host"), ShouldEqual, "overlay.internal")
+		})
+
+		Convey("Profile Sources", func() {
+			conf, _ := New(WithPath(path), WithProfile("prod"))
+			So(conf.ProfileSources(), ShouldResemble, []ProfileSource{
+				{Section: "cache", Key: "size", Profile: "prod", File: overlay},
+				{Section: "database", Key: "host", Profile: "prod", File: path},
+				{Section: "database", Key: "port", Profile: "", File: path},
+				{Section: "database", Key: "user", Profile: "prod", File: overlay},
+			})
+		})
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import "errors"
+
+// ErrReadOnly 에러는 ReadOnly를 통해 내용을 변경하려고 할 때 반환됩니다.
+var ErrReadOnly = errors.New("read only : configuration cannot be modified")
+
+// Getter 인터페이스는 config 내용을 읽는 함수들입니다.
+// 패키지가 *Configuration 대신 Getter에 의존하면 테스트에서 실제 파일 없이 대체할 수 있습니다.
+type Getter interface {
+	Find(section, key string) string
+	ExistValue(section, key string) (string, error)
+	GetSectionList() []string
+	GetKeyList(section string) []string
+}
+
+// Setter 인터페이스는 config 내용을 변경하는 함수들입니다.
+type Setter interface {
+	Write(section, key, value string) error
+	DeleteValue(section, key string) error
+	DeleteSection(section string) error
+}
+
+// GetSetter 인터페이스는 Getter와 Setter를 모두 구현한 config입니다.
+type GetSetter interface {
+	Getter
+	Setter
+}
+
+var (
+	_ GetSetter = (*Configuration)(nil)
+	_ GetSetter = (*ReadOnly)(nil)
+)
+
+// ReadOnly 구조체는 내용을 변경할 수 없는 config입니다.
+// 읽기 함수는 원본 Getter를 그대로 호출하며, 변경 함수는 항상 ErrReadOnly를 반환합니다.
+// 원본은 외부에 노출되지 않으므로 타입 변환으로 변경 함수를 사용할 수 없습니다.
+// =======================================
+//
+// plugin.Load(conf4g.NewReadOnly(conf))
+//
+// =======================================
+type ReadOnly struct {
+	source Getter
+}
+
+// NewReadOnly 함수는 source를 읽기 전용으로 감싼 ReadOnly를 반환합니다.
+// source가 이미 ReadOnly일 경우 그대로 반환합니다.
+func NewReadOnly(source Getter) *ReadOnly {
+	if readonly, ok := source.(*ReadOnly); ok {
+		return readonly
+	}
+	return &ReadOnly{source: source}
+}
+
+// Find 함수는 원본의 Find를 호출합니다.
+func (readonly *ReadOnly) Find(section, key string) string {
+	return readonly.source.Find(section, key)
+}
+
+// ExistValue 함수는 원본의 ExistValue를 호출합니다.
+func (readonly *ReadOnly) ExistValue(section, key string) (string, error) {
+	return readonly.source.ExistValue(section, key)
+}
+
+// GetSectionList 함수는 원본의 GetSectionList를 호출합니다.
+func (readonly *ReadOnly) GetSectionList() []string {
+	return readonly.source.GetSectionList()
+}
+
+// GetKeyList 함수는 원본의 GetKeyList를 호출합니다.
+func (readonly *ReadOnly) GetKeyList(section string) []string {
+	return readonly.source.GetKeyList(section)
+}
+
+// Write 함수는 아무것도 기록하지 않고 ErrReadOnly를 반환합니다.
+func (readonly *ReadOnly) Write(section, key, value string) error { return ErrReadOnly }
+
+// DeleteValue 함수는 아무것도 삭제하지 않고 ErrReadOnly를 반환합니다.
+func (readonly *ReadOnly) DeleteValue(section, key string) error { return ErrReadOnly }
+
+// DeleteSection 함수는 아무것도 삭제하지 않고 ErrReadOnly를 반환합니다.
+func (readonly *ReadOnly) DeleteSection(section string) error { return ErrReadOnly }
@@ -0,0 +1,90 @@
+package conf4g
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// mapGetter 타입은 파일 없이 Getter를 대체하는 테스트용 구현입니다.
+type mapGetter map[string]map[string]string
+
+func (getter mapGetter) Find(section, key string) string { return getter[section][key] }
+
+func (getter mapGetter) ExistValue(section, key string) (string, error) {
+	if value, ok := getter[section][key]; ok {
+		return value, nil
+	}
+	return "", errors.New("ExistValue : cannot find value")
+}
+
+func (getter mapGetter) GetSectionList() []string {
+	var list []string
+	for name := range getter {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (getter mapGetter) GetKeyList(section string) []string {
+	var list []string
+	for key := range getter[section] {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestReadOnlyFunction(t *testing.T) {
+
+	/*
+		variable := NewReadOnly(conf)
+
+		variable.Find("Section001", "Key001")		--> Value001
+		variable.Write("Section001", "Key001", "Value002")	--> ErrReadOnly
+	*/
+
+	Convey("ReadOnly Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[Section001]\nKey001=Value001\n")
+
+		conf, _ := New(WithPath(path))
+		readonly := NewReadOnly(conf)
+
+		Convey("ReadOnly Getter", func() {
+			So(readonly.Find("Section001", "Key001"), ShouldEqual, "Value001")
+			So(readonly.GetSectionList(), ShouldResemble, []string{"Section001"})
+			So(readonly.GetKeyList("Section001"), ShouldResemble, []string{"Key001"})
+
+			_, err := readonly.ExistValue("Section001", "Key002")
+			So(err, ShouldNotBeNil)
+
+			So(conf.Write("Section001", "Key001", "Value002"), ShouldBeNil)
+			So(readonly.Find("Section001", "Key001"), ShouldEqual, "Value002")
+		})
+
+		Convey("ReadOnly Setter", func() {
+			var setter Setter = readonly
+			So(errors.Is(setter.Write("Section001", "Key001", "Value002"), ErrReadOnly), ShouldBeTrue)
+			So(errors.Is(setter.DeleteValue("Section001", "Key001"), ErrReadOnly), ShouldBeTrue)
+			So(errors.Is(setter.DeleteSection("Section001"), ErrReadOnly), ShouldBeTrue)
+
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+
+			var getter Getter = readonly
+			_, ok := getter.(*Configuration)
+			So(ok, ShouldBeFalse)
+			So(NewReadOnly(readonly), ShouldEqual, readonly)
+		})
+
+		Convey("ReadOnly Mock", func() {
+			mock := NewReadOnly(mapGetter{"database": {"host": "localhost"}})
+			So(mock.Find("database", "host"), ShouldEqual, "localhost")
+			So(mock.GetKeyList("database"), ShouldResemble, []string{"host"})
+		})
+	})
+}
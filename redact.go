@@ -0,0 +1,266 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Redacted 는 민감한 value 대신 출력되는 문자열입니다.
+const Redacted = "******"
+
+// sensitiveTag 는 key의 주석에 포함될 경우 해당 key를 민감한 key로 지정하는 태그입니다.
+// =======================================
+//
+// ; @sensitive
+// password=p@ss
+//
+// =======================================
+const sensitiveTag = "@sensitive"
+
+// DefaultSensitivePatterns 는 SetSensitivePatterns를 지정하지 않았을 때 사용하는 key 이름 패턴입니다.
+var DefaultSensitivePatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*apikey*",
+	"*api_key*",
+	"*api-key*",
+	"*private_key*",
+	"*credential*",
+}
+
+// SetSensitivePatterns 함수는 민감한 key를 판별할 이름 패턴을 지정합니다.
+// 패턴은 path.Match 형식이며 대소문자를 구분하지 않습니다.
+// 패턴은 key 이름 또는 "section.key" 이름과 비교됩니다.
+// 패턴을 지정하지 않으면 DefaultSensitivePatterns를 사용합니다.
+func (conf *Configuration) SetSensitivePatterns(patterns ...string) {
+	conf.patterns = patterns
+}
+
+// MarkSensitive 함수는 지정된 section과 key를 민감한 key로 지정합니다.
+// section과 key는 SetNameMatch로 지정된 비교 방식에 따라 파일의 이름과 비교됩니다.
+func (conf *Configuration) MarkSensitive(section, key string) {
+	if conf.marked == nil {
+		conf.marked = map[string]bool{}
+	}
+	conf.marked[joinSection(section, key)] = true
+}
+
+// MarkSensitiveSchema 함수는 struct의 `conf:"name,sensitive"` 태그가 지정된 field를 section의 민감한 key로 지정합니다.
+// key 이름과 하위 section은 BindSubtree와 같이 대소문자를 구분하지 않고 대응되며, struct 타입의 field는 한 단계 아래 section입니다.
+// schema가 struct 또는 struct의 pointer가 아닐 경우 에러를 반환합니다.
+// =======================================
+//
+// type Database struct { User string; Password string `conf:"password,sensitive"`; Replica struct{ Token string `conf:",sensitive"` } }
+//
+// MarkSensitiveSchema("database", Database{})
+//
+// --> database.password, database.Replica.Token
+//
+// =======================================
+func (conf *Configuration) MarkSensitiveSchema(section string, schema interface{}) error {
+	structtype := reflect.TypeOf(schema)
+	if structtype != nil && structtype.Kind() == reflect.Ptr {
+		structtype = structtype.Elem()
+	}
+	if structtype == nil || structtype.Kind() != reflect.Struct {
+		return errors.New("MarkSensitiveSchema : schema must be a struct")
+	}
+
+	conf.markSchema(section, structtype)
+	return nil
+}
+
+// markSchema 함수는 struct 타입의 sensitive field를 section의 민감한 key로 지정합니다.
+func (conf *Configuration) markSchema(section string, structtype reflect.Type) {
+	for i := 0; i < structtype.NumField(); i++ {
+		field := structtype.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key, sensitive, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			conf.markSchema(joinSection(section, key), field.Type)
+			continue
+		}
+		if sensitive {
+			if conf.schema == nil {
+				conf.schema = map[string]bool{}
+			}
+			conf.schema[strings.ToLower(joinSection(section, key))] = true
+		}
+	}
+}
+
+// IsSensitive 함수는 지정된 section과 key의 value가 출력 시 가려져야 하는지 확인합니다.
+// 이름 패턴, MarkSensitive 및 MarkSensitiveSchema, 주석의 @sensitive 태그, secret value 중 하나에 해당하면 true를 반환합니다.
+func (conf *Configuration) IsSensitive(section, key string) bool {
+	conf.Read()
+	return conf.isSensitive(section, key)
+}
+
+func (conf *Configuration) isSensitive(section, key string) bool {
+	section, key = conf.resolve(section, key)
+	if conf.isMarked(section, key) {
+		return true
+	}
+	if targetsection, ok := conf.sections[section]; ok {
+		if targetsection.sensitive[key] || isSecret(targetsection.data[key]) {
+			return true
+		}
+	}
+
+	patterns := conf.patterns
+	if patterns == nil {
+		patterns = DefaultSensitivePatterns
+	}
+	return matchSensitive(patterns, section, key)
+}
+
+// isMarked 함수는 section과 key가 MarkSensitive로 지정되었는지 이름의 비교 방식에 따라 확인합니다.
+// MarkSensitiveSchema로 지정된 key는 대소문자를 구분하지 않고 비교합니다.
+func (conf *Configuration) isMarked(section, key string) bool {
+	name := joinSection(section, key)
+	if conf.marked[name] || conf.schema[strings.ToLower(name)] {
+		return true
+	}
+
+	fold := conf.fold()
+	for marked := range conf.marked {
+		if sameName(fold, marked, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactText 함수는 path 파일의 UTF-8 내용에서 민감한 value를 Redacted로 가린 내용을 반환합니다.
+// 민감한 value가 없을 경우 내용을 그대로 반환하며, 내용을 해석할 수 없을 경우 에러를 반환합니다.
+func (conf *Configuration) redactText(path string, text []byte) ([]byte, error) {
+	doc, err := conf.formatOf(path).Decode(text)
+	if err != nil {
+		return nil, errors.New(fmt.Sprint("cannot redact ", path, ", ", err))
+	}
+
+	redacted := false
+	for _, sec := range doc.Sections {
+		for _, entry := range sec.Entries {
+			if conf.redact(sec.Name, entry.Key, entry.Value) == Redacted || hasSensitiveTag(entry.Comments) {
+				entry.Value, redacted = Redacted, true
+			}
+		}
+	}
+	if !redacted {
+		return text, nil
+	}
+
+	masked, eerr := conf.formatOf(path).Encode(doc)
+	if eerr != nil {
+		return nil, errors.New(fmt.Sprint("cannot redact ", path, ", ", eerr))
+	}
+	return masked, nil
+}
+
+// RedactedSnapshot 함수는 민감한 value를 가린 config 내용 전체를 반환합니다.
+// 지원 요청용 번들 등 외부로 내보내는 용도로 사용합니다.
+// global 영역은 공백 section으로 포함됩니다.
+func (conf *Configuration) RedactedSnapshot() map[string]map[string]string {
+	conf.Read()
+
+	snapshot := map[string]map[string]string{}
+	for name, targetsection := range conf.sections {
+		values := map[string]string{}
+		for key, value := range targetsection.data {
+			values[key] = conf.redact(name, key, value)
+		}
+		snapshot[name] = values
+	}
+	return snapshot
+}
+
+// String 함수는 민감한 value를 가린 config 내용을 INI 형식의 문자열로 반환합니다.
+func (conf *Configuration) String() string {
+	snapshot := conf.RedactedSnapshot()
+
+	var names []string
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var text bytes.Buffer
+	for _, name := range names {
+		if name != "" {
+			fmt.Fprintf(&text, "[%s]\n", name)
+		}
+
+		var keys []string
+		for key := range snapshot[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(&text, "%s=%s\n", key, snapshot[name][key])
+		}
+	}
+	return text.String()
+}
+
+// redact 함수는 민감한 key의 value를 Redacted로 바꾸어 반환합니다.
+// 아직 기록되지 않은 secret value도 가려집니다.
+func (conf *Configuration) redact(section, key, value string) string {
+	if isSecret(value) || conf.isSensitive(section, key) {
+		return Redacted
+	}
+	return value
+}
+
+// redactValues 함수는 반복된 key의 value를 각각 가린 후 ", "로 연결하여 반환합니다.
+func (conf *Configuration) redactValues(section, key string, values []string) string {
+	redacted := make([]string, len(values))
+	for i, value := range values {
+		redacted[i] = conf.redact(section, key, value)
+	}
+	return strings.Join(redacted, ", ")
+}
+
+// matchSensitive 함수는 key 이름이 패턴 중 하나와 일치하는지 확인합니다.
+func matchSensitive(patterns []string, section, key string) bool {
+	names := []string{strings.ToLower(key), strings.ToLower(joinSection(section, key))}
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSensitiveTag 함수는 주석에 @sensitive 태그가 포함되어 있는지 확인합니다.
+func hasSensitiveTag(comments []string) bool {
+	for _, comment := range comments {
+		if strings.Contains(comment, sensitiveTag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package conf4g
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedactFunction(t *testing.T) {
+
+	/*
+		configdata :
+
+		[Database]
+		User=root
+		Password=p@ss
+		; @sensitive
+		Host=db.internal
+
+		variable.String()
+
+		-->
+		[Database]
+		Host=******
+		Password=******
+		User=root
+	*/
+
+	Convey("Redact Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[Database]\nUser=root\nPassword=p@ss\n; @sensitive\nHost=db.internal\n[Server]\nPort=8080\n")
+		conf := makeTestConfig(path)
+
+		Convey("Redact Default", func() {
+			So(conf.IsSensitive("Database", "Password"), ShouldBeTrue)
+			So(conf.IsSensitive("Database", "Host"), ShouldBeTrue)
+			So(conf.IsSensitive("Database", "User"), ShouldBeFalse)
+			So(conf.IsSensitive("Other", "api_token"), ShouldBeTrue)
+
+			So(conf.String(), ShouldEqual, "[Database]\nHost=******\nPassword=******\nUser=root\n[Server]\nPort=8080\n")
+		})
+
+		Convey("Redact Snapshot", func() {
+			conf.MarkSensitive("Server", "Port")
+
+			snapshot := conf.RedactedSnapshot()
+			So(snapshot["Server"]["Port"], ShouldEqual, Redacted)
+			So(snapshot["Database"]["User"], ShouldEqual, "root")
+			So(conf.Find("Database", "Password"), ShouldEqual, "p@ss")
+		})
+
+		Convey("Redact Pattern", func() {
+			conf.SetSensitivePatterns("database.user")
+
+			So(conf.IsSensitive("Database", "User"), ShouldBeTrue)
+			So(conf.IsSensitive("Database", "Password"), ShouldBeFalse)
+			So(conf.IsSensitive("Database", "Host"), ShouldBeTrue)
+		})
+
+		Convey("Redact Schema", func() {
+			type schema struct {
+				User   string
+				Port   int    `conf:"port,sensitive"`
+				Ignore string `conf:"-"`
+				Web    struct {
+					Cert string `conf:",sensitive"`
+				}
+			}
+
+			So(conf.MarkSensitiveSchema("Server", &schema{}), ShouldBeNil)
+			So(conf.IsSensitive("Server", "Port"), ShouldBeTrue)
+			So(conf.IsSensitive("Server", "User"), ShouldBeFalse)
+			So(conf.IsSensitive("Server.Web", "Cert"), ShouldBeTrue)
+			So(conf.RedactedSnapshot()["Server"]["Port"], ShouldEqual, Redacted)
+
+			So(conf.MarkSensitiveSchema("Server", "schema"), ShouldNotBeNil)
+		})
+
+		Convey("Redact Secret", func() {
+			conf.SetKeyProvider(StaticKeyProvider([]byte("0123456789abcdef0123456789abcdef")))
+			conf.WriteSecret("Server", "Name", "web")
+
+			So(conf.IsSensitive("Server", "Name"), ShouldBeTrue)
+			So(conf.RedactedSnapshot()["Server"]["Name"], ShouldEqual, Redacted)
+		})
+
+		Convey("Redact Error", func() {
+			_, err := conf.FindInt("Database", "Password")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldNotContainSubstring, "p@ss")
+			So(err.Error(), ShouldContainSubstring, Redacted)
+		})
+	})
+}
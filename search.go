@@ -0,0 +1,164 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SearchPath의 Rules에 사용하는 탐색 규칙입니다.
+// =======================================
+//
+// explicit	: SearchPath.Explicit에 지정된 경로
+// xdg		: $XDG_CONFIG_HOME/{app}/{name}
+// home		: ~/.config/{app}/{name}
+// system	: /etc/{app}/{name} (windows : %ProgramData%\{app}\{name})
+// executable	: {실행 파일 폴더}/{name}
+// workdir	: {현재 작업 폴더}/{name}
+// parents	: 현재 작업 폴더부터 상위 폴더로 올라가며 찾은 {name} (.editorconfig 방식)
+//
+// =======================================
+const (
+	RuleExplicit   = "explicit"
+	RuleXDG        = "xdg"
+	RuleHome       = "home"
+	RuleSystem     = "system"
+	RuleExecutable = "executable"
+	RuleWorkDir    = "workdir"
+	RuleParents    = "parents"
+)
+
+// DefaultSearchRules 는 SearchPath.Rules를 지정하지 않았을 때 사용하는 탐색 순서입니다.
+var DefaultSearchRules = []string{RuleExplicit, RuleXDG, RuleHome, RuleSystem, RuleExecutable, RuleWorkDir}
+
+// ErrConfigNotFound 에러는 탐색 경로에서 config 파일을 찾을 수 없을 때 반환됩니다.
+var ErrConfigNotFound = errors.New("search : configuration not found")
+
+// SearchPath 구조체는 config 파일을 찾을 후보 경로의 규칙입니다.
+// =======================================
+//
+// App		: 폴더 이름입니다. 지정하지 않을 경우 실행 파일 이름을 사용합니다.
+// Name		: 파일 이름입니다. 지정하지 않을 경우 {App}.ini를 사용합니다.
+// Explicit	: explicit 규칙에 사용할 경로입니다. 상대 경로는 현재 작업 폴더를 기준으로 합니다.
+// Rules	: 탐색 순서입니다. 지정하지 않을 경우 DefaultSearchRules를 사용합니다.
+//
+// =======================================
+type SearchPath struct {
+	App      string
+	Name     string
+	Explicit string
+	Rules    []string
+}
+
+// Candidate 구조체는 탐색 규칙에 따른 하나의 후보 경로입니다.
+type Candidate struct {
+	Rule string
+	Path string
+}
+
+// Candidates 함수는 탐색 순서대로 모든 후보 경로를 반환합니다.
+// 환경변수가 없는 등 경로를 만들 수 없는 규칙은 제외됩니다.
+func (search SearchPath) Candidates() []Candidate {
+	app := search.App
+	if app == "" {
+		app = strings.SplitN(filepath.Base(os.Args[0]), ".", 2)[0]
+	}
+	name := search.Name
+	if name == "" {
+		name = app + ".ini"
+	}
+	rules := search.Rules
+	if rules == nil {
+		rules = DefaultSearchRules
+	}
+
+	var candidates []Candidate
+	add := func(rule string, dir ...string) {
+		candidates = append(candidates, Candidate{Rule: rule, Path: filepath.Join(append(dir, name)...)})
+	}
+
+	for _, rule := range rules {
+		switch rule {
+		case RuleExplicit:
+			if search.Explicit != "" {
+				path, _ := filepath.Abs(search.Explicit)
+				candidates = append(candidates, Candidate{Rule: rule, Path: path})
+			}
+		case RuleXDG:
+			if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+				add(rule, dir, app)
+			}
+		case RuleHome:
+			if dir, err := os.UserHomeDir(); err == nil {
+				add(rule, dir, ".config", app)
+			}
+		case RuleSystem:
+			if runtime.GOOS == "windows" {
+				if dir := os.Getenv("ProgramData"); dir != "" {
+					add(rule, dir, app)
+				}
+			} else {
+				add(rule, "/etc", app)
+			}
+		case RuleExecutable:
+			if executable, err := os.Executable(); err == nil {
+				if resolved, lerr := filepath.EvalSymlinks(executable); lerr == nil {
+					executable = resolved
+				}
+				add(rule, filepath.Dir(executable))
+			}
+		case RuleWorkDir:
+			if dir, err := os.Getwd(); err == nil {
+				add(rule, dir)
+			}
+		case RuleParents:
+			if dir, err := os.Getwd(); err == nil {
+				for {
+					add(rule, dir)
+					parent := filepath.Dir(dir)
+					if parent == dir {
+						break
+					}
+					dir = parent
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// Resolve 함수는 탐색 순서대로 처음 존재하는 config 파일과 해당 규칙을 반환합니다.
+// 파일을 찾을 수 없을 경우 확인한 경로 목록과 함께 ErrConfigNotFound를 반환합니다.
+func (search SearchPath) Resolve() (Candidate, error) {
+	candidates := search.Candidates()
+
+	var checked []string
+	for _, candidate := range candidates {
+		if ftype, err := exists(candidate.Path); err == nil && ftype == 1 {
+			return candidate, nil
+		}
+		checked = append(checked, candidate.Path)
+	}
+	return Candidate{}, fmt.Errorf("%w : %s", ErrConfigNotFound, strings.Join(checked, ", "))
+}
+
+// Search 함수는 탐색 경로에서 찾은 config 파일을 사용하도록 Configuration을 초기화합니다.
+// 찾은 경로와 규칙을 반환하며, 파일을 찾을 수 없을 경우 ErrConfigNotFound를 반환합니다.
+func (conf *Configuration) Search(search SearchPath) (Candidate, error) {
+	found, err := search.Resolve()
+	if err != nil {
+		return found, fmt.Errorf("Search : %w", err)
+	}
+	if ierr := conf.Initialize(found.Path); ierr != nil {
+		return found, errors.New(fmt.Sprint("Search : ", ierr))
+	}
+	return found, nil
+}
@@ -0,0 +1,74 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchFunction(t *testing.T) {
+
+	/*
+		search := SearchPath{App: "app", Explicit: flagpath}
+		variable.Search(search)
+
+		--> explicit, $XDG_CONFIG_HOME/app/app.ini, ~/.config/app/app.ini, /etc/app/app.ini, ...
+	*/
+
+	Convey("Search Function", t, func() {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+		t.Setenv("HOME", filepath.Join(dir, "home"))
+
+		search := SearchPath{App: "conf4g-search-test"}
+
+		Convey("Search Candidates", func() {
+			candidates := search.Candidates()
+			So(candidates[0], ShouldResemble, Candidate{Rule: RuleXDG, Path: filepath.Join(dir, "xdg", "conf4g-search-test", "conf4g-search-test.ini")})
+			So(candidates[1], ShouldResemble, Candidate{Rule: RuleHome, Path: filepath.Join(dir, "home", ".config", "conf4g-search-test", "conf4g-search-test.ini")})
+		})
+
+		Convey("Search Order", func() {
+			_, err := search.Resolve()
+			So(errors.Is(err, ErrConfigNotFound), ShouldBeTrue)
+
+			home := filepath.Join(dir, "home", ".config", "conf4g-search-test", "conf4g-search-test.ini")
+			writeTestFile(home, "[Section001]\nKey001=Home\n")
+
+			found, err := search.Resolve()
+			So(err, ShouldBeNil)
+			So(found, ShouldResemble, Candidate{Rule: RuleHome, Path: home})
+
+			explicit := filepath.Join(dir, "explicit.ini")
+			writeTestFile(explicit, "[Section001]\nKey001=Explicit\n")
+			search.Explicit = explicit
+
+			conf := MakeConfig()
+			found, err = conf.Search(search)
+			So(err, ShouldBeNil)
+			So(found.Rule, ShouldEqual, RuleExplicit)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Explicit")
+		})
+
+		Convey("Search Parents", func() {
+			writeTestFile(filepath.Join(dir, "repo", ".tool.ini"), "[Section001]\nKey001=Repo\n")
+			nested := filepath.Join(dir, "repo", "a", "b")
+			os.MkdirAll(nested, os.ModePerm)
+
+			wd, _ := os.Getwd()
+			os.Chdir(nested)
+			defer os.Chdir(wd)
+
+			found, err := SearchPath{Name: ".tool.ini", Rules: []string{RuleParents}}.Resolve()
+			So(err, ShouldBeNil)
+			So(found.Rule, ShouldEqual, RuleParents)
+
+			expected, _ := filepath.EvalSymlinks(filepath.Join(dir, "repo", ".tool.ini"))
+			actual, _ := filepath.EvalSymlinks(found.Path)
+			So(actual, ShouldEqual, expected)
+		})
+	})
+}
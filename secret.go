@@ -0,0 +1,273 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 암호화된 secret value의 접두어입니다.
+// =======================================
+//
+// password = enc:v1:{base64url(nonce + ciphertext)}
+//
+// v1 : AES-256-GCM, 12 byte nonce
+//
+// =======================================
+const secretV1Prefix = "enc:v1:"
+
+// ErrSecret 에러는 secret value를 암호화 또는 복호화할 수 없을 때 반환됩니다.
+var ErrSecret = errors.New("secret : cannot process secret value")
+
+// KeyProvider 인터페이스는 secret value의 암호화에 사용할 키를 제공합니다.
+// Key는 32 byte의 AES-256 키를 반환해야 합니다.
+type KeyProvider interface {
+	Key() ([]byte, error)
+}
+
+// keyFunc 타입은 함수를 KeyProvider로 사용합니다.
+type keyFunc func() ([]byte, error)
+
+func (f keyFunc) Key() ([]byte, error) { return f() }
+
+// FileKeyProvider 함수는 파일에 저장된 키를 사용하는 KeyProvider를 반환합니다.
+// 키의 형식은 parseKey 함수를 참고합니다.
+func FileKeyProvider(path string) KeyProvider {
+	return keyFunc(func() ([]byte, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w : cannot read key file, %v", ErrSecret, err)
+		}
+		return parseKey(string(data))
+	})
+}
+
+// EnvKeyProvider 함수는 환경변수에 저장된 키를 사용하는 KeyProvider를 반환합니다.
+// 키의 형식은 parseKey 함수를 참고합니다.
+func EnvKeyProvider(name string) KeyProvider {
+	return keyFunc(func() ([]byte, error) {
+		material, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("%w : environment variable %s is not set", ErrSecret, name)
+		}
+		return parseKey(material)
+	})
+}
+
+// StaticKeyProvider 함수는 지정된 키를 그대로 사용하는 KeyProvider를 반환합니다.
+func StaticKeyProvider(key []byte) KeyProvider {
+	return keyFunc(func() ([]byte, error) {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w : key must be 32 bytes", ErrSecret)
+		}
+		return key, nil
+	})
+}
+
+// parseKey 함수는 키 문자열을 32 byte 키로 변환합니다.
+// 64자리 hex 또는 32 byte의 base64 문자열은 그대로 사용하며,
+// 그 외의 문자열은 passphrase로 간주하여 SHA-256 값을 사용합니다.
+func parseKey(material string) ([]byte, error) {
+	material = strings.TrimSpace(material)
+	if material == "" {
+		return nil, fmt.Errorf("%w : empty key", ErrSecret)
+	}
+
+	if key, err := hex.DecodeString(material); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(material); err == nil && len(key) == 32 {
+		return key, nil
+	}
+
+	sum := sha256.Sum256([]byte(material))
+	return sum[:], nil
+}
+
+// SetKeyProvider 함수는 secret value의 암호화/복호화에 사용할 KeyProvider를 지정합니다.
+// nil을 지정하면 secret value를 복호화하지 않습니다.
+func (conf *Configuration) SetKeyProvider(provider KeyProvider) {
+	conf.keys = provider
+}
+
+// WriteSecret 함수는 value를 암호화하여 config 파일에 기록합니다.
+// KeyProvider가 지정되지 않았을 경우 에러를 반환합니다.
+func (conf *Configuration) WriteSecret(section, key, value string) error {
+	if value == "" {
+		return errors.New("WriteSecret : missing value")
+	}
+
+	secret, err := encryptSecret(conf.keys, value)
+	if err != nil {
+		return errors.New(fmt.Sprint("WriteSecret : ", err))
+	}
+	return conf.Write(section, key, secret)
+}
+
+// IsSecret 함수는 지정된 section과 key의 value가 암호화되어 있는지 확인합니다.
+func (conf *Configuration) IsSecret(section, key string) bool {
+	conf.Read()
+	section, key = conf.resolve(section, key)
+
+	if targetsection, ok := conf.sections[section]; ok {
+		return isSecret(targetsection.data[key])
+	}
+	return false
+}
+
+// RotateKey 함수는 모든 secret value를 새로운 키로 다시 암호화합니다.
+// 모든 value를 먼저 복호화하고 모든 파일의 내용을 변환하여 검증한 후 저장하므로, 실패할 경우 파일은 변경되지 않습니다.
+// 파일은 병합된 순서대로 임시 파일에 기록한 후 교체하며, 저장 도중 실패할 경우 이미 저장한 파일을 원래의 내용으로 되돌립니다.
+func (conf *Configuration) RotateKey(provider KeyProvider) error {
+	conf.Read()
+	conf.mu.Lock()
+
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+	}()
+
+	if provider == nil {
+		return errors.New("RotateKey : missing key provider")
+	}
+
+	var paths []string
+	encoded := map[string][]byte{}
+	for _, path := range conf.sources {
+		doc, derr := conf.readDocument(path)
+		if derr != nil {
+			return errors.New(fmt.Sprint("RotateKey : cannot read configuration ", derr))
+		}
+
+		changed := false
+		for _, sec := range doc.Sections {
+			for _, entry := range sec.Entries {
+				if !isSecret(entry.Value) {
+					continue
+				}
+				plain, perr := decryptSecret(conf.keys, entry.Value)
+				if perr != nil {
+					return errors.New(fmt.Sprint("RotateKey : ", joinSection(sec.Name, entry.Key), " ", perr))
+				}
+				if entry.Value, perr = encryptSecret(provider, plain); perr != nil {
+					return errors.New(fmt.Sprint("RotateKey : ", perr))
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		data, eerr := conf.encodeDocument(doc, path)
+		if eerr != nil {
+			return errors.New(fmt.Sprint("RotateKey : cannot save configuration ", eerr))
+		}
+		paths = append(paths, path)
+		encoded[path] = data
+	}
+
+	originals := map[string][]byte{}
+	for _, path := range paths {
+		original, rerr := conf.storage().ReadFile(path)
+		if rerr != nil {
+			return errors.New(fmt.Sprint("RotateKey : cannot read configuration ", rerr))
+		}
+		originals[path] = original
+	}
+
+	for i, path := range paths {
+		if serr := conf.storeDocument(path, encoded[path]); serr != nil {
+			for _, saved := range paths[:i] {
+				conf.storage().WriteFile(saved, originals[saved], conf.mode())
+			}
+			return errors.New(fmt.Sprint("RotateKey : cannot save configuration ", serr))
+		}
+	}
+
+	conf.keys = provider
+	return nil
+}
+
+// reveal 함수는 secret value를 복호화하여 반환합니다.
+// secret value가 아닐 경우 그대로 반환합니다.
+func (conf *Configuration) reveal(value string) (string, error) {
+	if !isSecret(value) {
+		return value, nil
+	}
+	return decryptSecret(conf.keys, value)
+}
+
+// isSecret 함수는 value가 지원하는 형식의 secret value인지 확인합니다.
+// enc:v1: 이외의 "enc:" 로 시작하는 value는 일반 value로 취급합니다.
+func isSecret(value string) bool {
+	return strings.HasPrefix(value, secretV1Prefix)
+}
+
+// encryptSecret 함수는 value를 AES-256-GCM으로 암호화하여 enc:v1: 형식으로 반환합니다.
+func encryptSecret(provider KeyProvider, value string) (string, error) {
+	aead, err := secretCipher(provider)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, rerr := rand.Read(nonce); rerr != nil {
+		return "", fmt.Errorf("%w : %v", ErrSecret, rerr)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return secretV1Prefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret 함수는 enc:v1: 형식의 value를 복호화합니다.
+func decryptSecret(provider KeyProvider, value string) (string, error) {
+	if !strings.HasPrefix(value, secretV1Prefix) {
+		return "", fmt.Errorf("%w : unsupported secret version", ErrSecret)
+	}
+
+	aead, err := secretCipher(provider)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, derr := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, secretV1Prefix))
+	if derr != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("%w : malformed secret value", ErrSecret)
+	}
+
+	plain, oerr := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if oerr != nil {
+		return "", fmt.Errorf("%w : wrong key or corrupted value", ErrSecret)
+	}
+	return string(plain), nil
+}
+
+func secretCipher(provider KeyProvider) (cipher.AEAD, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("%w : missing key provider", ErrSecret)
+	}
+
+	key, err := provider.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	block, berr := aes.NewCipher(key)
+	if berr != nil {
+		return nil, fmt.Errorf("%w : %v", ErrSecret, berr)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,138 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSecretFunction(t *testing.T) {
+
+	/*
+		variable.WriteSecret(section, key, value)
+
+		variable.SetKeyProvider(EnvKeyProvider("APP_KEY"))
+		variable.WriteSecret("Database", "Password", "p@ss")
+
+		-->
+		[Database]
+		Password=enc:v1:...
+
+		variable.Find("Database", "Password")
+
+		--> p@ss
+	*/
+
+	Convey("Secret Function", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.ini")
+		writeTestFile(path, "[Database]\nUser=root\n")
+
+		key := StaticKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+
+		Convey("Secret Write", func() {
+			conf := makeTestConfig(path)
+			conf.SetKeyProvider(key)
+
+			So(conf.WriteSecret("Database", "Password", "p@ss"), ShouldBeNil)
+			So(conf.IsSecret("Database", "Password"), ShouldBeTrue)
+			So(conf.IsSecret("Database", "User"), ShouldBeFalse)
+			So(conf.Find("Database", "Password"), ShouldEqual, "p@ss")
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "Password=enc:v1:")
+			So(string(data), ShouldNotContainSubstring, "p@ss")
+		})
+
+		Convey("Secret Typed", func() {
+			conf := makeTestConfig(path)
+			conf.SetKeyProvider(key)
+			conf.WriteSecret("Database", "Port", "5432")
+
+			port, err := conf.FindInt("Database", "Port")
+			So(err, ShouldBeNil)
+			So(port, ShouldEqual, 5432)
+		})
+
+		Convey("Secret Missing Key", func() {
+			conf := makeTestConfig(path)
+			So(conf.WriteSecret("Database", "Password", "p@ss"), ShouldNotBeNil)
+
+			conf.SetKeyProvider(key)
+			conf.WriteSecret("Database", "Password", "p@ss")
+			conf.SetKeyProvider(nil)
+
+			So(conf.Find("Database", "Password"), ShouldBeEmpty)
+			_, err := conf.ExistValue("Database", "Password")
+			So(errors.Is(err, ErrSecret), ShouldBeTrue)
+		})
+
+		Convey("Secret Wrong Key", func() {
+			conf := makeTestConfig(path)
+			conf.SetKeyProvider(key)
+			conf.WriteSecret("Database", "Password", "p@ss")
+
+			conf.SetKeyProvider(StaticKeyProvider([]byte("fedcba9876543210fedcba9876543210")))
+			_, err := conf.ExistValue("Database", "Password")
+			So(errors.Is(err, ErrSecret), ShouldBeTrue)
+		})
+
+		Convey("Secret Rotate", func() {
+			conf := makeTestConfig(path)
+			conf.SetKeyProvider(key)
+			conf.WriteSecret("Database", "Password", "p@ss")
+			before := conf.sections["Database"].data["Password"]
+
+			rotated := StaticKeyProvider([]byte("fedcba9876543210fedcba9876543210"))
+			So(conf.RotateKey(rotated), ShouldBeNil)
+			So(conf.sections["Database"].data["Password"], ShouldNotEqual, before)
+			So(conf.Find("Database", "Password"), ShouldEqual, "p@ss")
+
+			conf.SetKeyProvider(key)
+			So(conf.RotateKey(rotated), ShouldNotBeNil)
+		})
+
+		Convey("Secret Rotate Include", func() {
+			writeTestFile(filepath.Join(dir, "extra.ini"), "[Cache]\nHost=local\n")
+			writeTestFile(path, "include = extra.ini\n[Database]\nUser=root\nLabel=enc:plain\n")
+			conf := makeTestConfig(path)
+			conf.SetKeyProvider(key)
+			So(conf.WriteSecret("Database", "Password", "p@ss"), ShouldBeNil)
+			So(conf.IsSecret("Database", "Label"), ShouldBeFalse)
+			So(conf.Find("Database", "Label"), ShouldEqual, "enc:plain")
+
+			writeTestFile(filepath.Join(dir, "extra.ini"), "[Cache]\nToken=enc:v1:broken\n")
+			before, _ := os.ReadFile(path)
+
+			rotated := StaticKeyProvider([]byte("fedcba9876543210fedcba9876543210"))
+			So(conf.RotateKey(rotated), ShouldNotBeNil)
+
+			after, _ := os.ReadFile(path)
+			So(string(after), ShouldEqual, string(before))
+			So(conf.Find("Database", "Password"), ShouldEqual, "p@ss")
+		})
+
+		Convey("Secret Provider", func() {
+			keypath := filepath.Join(dir, "secret.key")
+			writeTestFile(keypath, strings.Repeat("ab", 32)+"\n")
+
+			filekey, err := FileKeyProvider(keypath).Key()
+			So(err, ShouldBeNil)
+			So(filekey, ShouldHaveLength, 32)
+
+			os.Setenv("CONF4G_TEST_KEY", "passphrase")
+			defer os.Unsetenv("CONF4G_TEST_KEY")
+
+			envkey, err := EnvKeyProvider("CONF4G_TEST_KEY").Key()
+			So(err, ShouldBeNil)
+			So(envkey, ShouldHaveLength, 32)
+
+			_, err = EnvKeyProvider("CONF4G_TEST_MISSING").Key()
+			So(errors.Is(err, ErrSecret), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Storage 인터페이스는 config 파일과 include, conf.d 파일을 읽고 쓰는 저장소입니다.
+// 지정하지 않을 경우 로컬 파일 시스템(OSStorage)을 사용합니다.
+// 버전 백업과 감사 기록 파일은 Storage와 관계없이 로컬 파일 시스템을 사용합니다.
+// =======================================
+//
+// ReadFile	: 파일의 내용을 반환합니다.
+// WriteFile	: 파일의 내용을 한번에 교체합니다. 기존 파일의 권한은 유지하며, 신규 파일은 perm을 사용합니다.
+// Stat		: 파일 정보를 반환합니다. 파일이 없을 경우 fs.ErrNotExist를 포함한 에러를 반환합니다.
+// Glob		: filepath.Match 형식의 패턴과 일치하는 파일 목록을 반환합니다.
+// Remove	: 파일을 삭제합니다.
+// MkdirAll	: 폴더를 생성합니다.
+//
+// =======================================
+type Storage interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	Stat(path string) (os.FileInfo, error)
+	Glob(pattern string) ([]string, error)
+	Remove(path string) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// chmodStorage 인터페이스는 기존 파일의 권한을 변경할 수 있는 Storage입니다.
+// WriteFile은 기존 파일의 권한을 유지하므로, 권한을 지정해야 하는 .bak 파일에 사용합니다.
+type chmodStorage interface {
+	Chmod(path string, perm os.FileMode) error
+}
+
+// OSStorage 구조체는 로컬 파일 시스템을 사용하는 Storage입니다.
+// WriteFile은 임시 파일에 기록한 후 교체합니다.
+type OSStorage struct{}
+
+func (OSStorage) ReadFile(path string) ([]byte, error) { return os.ReadFile(path) }
+
+func (OSStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, data, perm)
+}
+
+func (OSStorage) Stat(path string) (os.FileInfo, error) { return os.Stat(path) }
+
+func (OSStorage) Glob(pattern string) ([]string, error) { return filepath.Glob(pattern) }
+
+func (OSStorage) Remove(path string) error { return os.Remove(path) }
+
+func (OSStorage) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+// Chmod 함수는 파일의 권한을 변경합니다.
+func (OSStorage) Chmod(path string, perm os.FileMode) error { return os.Chmod(path, perm) }
+
+// MemoryStorage 구조체는 메모리에 파일을 보관하는 Storage입니다.
+// 테스트 또는 파일 시스템을 사용할 수 없는 환경에서 사용합니다.
+type MemoryStorage struct {
+	mu    sync.Mutex
+	files map[string]*memoryFile
+}
+
+type memoryFile struct {
+	name    string
+	data    []byte
+	mode    os.FileMode
+	modtime time.Time
+}
+
+// NewMemoryStorage 함수는 비어있는 MemoryStorage를 반환합니다.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{files: map[string]*memoryFile{}}
+}
+
+func (storage *MemoryStorage) ReadFile(path string) ([]byte, error) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	file, ok := storage.files[filepath.Clean(path)]
+	if !ok || file.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: fs.ErrNotExist}
+	}
+	return append([]byte{}, file.data...), nil
+}
+
+func (storage *MemoryStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	path = filepath.Clean(path)
+	if file, ok := storage.files[path]; ok {
+		if file.mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: path, Err: errors.New("is a directory")}
+		}
+		perm = file.mode.Perm()
+	}
+	storage.files[path] = &memoryFile{name: filepath.Base(path), data: append([]byte{}, data...), mode: perm.Perm(), modtime: time.Now()}
+	return nil
+}
+
+// Chmod 함수는 파일의 권한을 변경합니다.
+func (storage *MemoryStorage) Chmod(path string, perm os.FileMode) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	file, ok := storage.files[filepath.Clean(path)]
+	if !ok {
+		return &fs.PathError{Op: "chmod", Path: path, Err: fs.ErrNotExist}
+	}
+	file.mode = file.mode&^os.ModePerm | perm.Perm()
+	return nil
+}
+
+func (storage *MemoryStorage) Stat(path string) (os.FileInfo, error) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	file, ok := storage.files[filepath.Clean(path)]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+	}
+	return file, nil
+}
+
+func (storage *MemoryStorage) Glob(pattern string) ([]string, error) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	var matches []string
+	for path := range storage.files {
+		ok, err := filepath.Match(pattern, path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func (storage *MemoryStorage) Remove(path string) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	path = filepath.Clean(path)
+	if _, ok := storage.files[path]; !ok {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	delete(storage.files, path)
+	return nil
+}
+
+func (storage *MemoryStorage) MkdirAll(path string, perm os.FileMode) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	for path = filepath.Clean(path); ; path = filepath.Dir(path) {
+		if _, ok := storage.files[path]; !ok {
+			storage.files[path] = &memoryFile{name: filepath.Base(path), mode: os.ModeDir | perm.Perm(), modtime: time.Now()}
+		}
+		if filepath.Dir(path) == path {
+			return nil
+		}
+	}
+}
+
+func (file *memoryFile) Name() string       { return file.name }
+func (file *memoryFile) Size() int64        { return int64(len(file.data)) }
+func (file *memoryFile) Mode() os.FileMode  { return file.mode }
+func (file *memoryFile) ModTime() time.Time { return file.modtime }
+func (file *memoryFile) IsDir() bool        { return file.mode.IsDir() }
+func (file *memoryFile) Sys() interface{}   { return nil }
+
+// storage 함수는 Configuration에 지정된 Storage를 반환하며, 지정되지 않았을 경우 OSStorage를 반환합니다.
+func (conf *Configuration) storage() Storage {
+	if conf.store == nil {
+		return OSStorage{}
+	}
+	return conf.store
+}
+
+// stat 함수는 Storage에서 대상의 종류를 확인합니다.
+// 반환값은 Exists 함수와 같이 0(폴더), 1(파일), 2(기타)이며, 존재하지 않을 경우 에러를 반환합니다.
+func (conf *Configuration) stat(target string) (int, error) {
+	fi, err := conf.storage().Stat(filepath.Clean(target))
+	if err != nil {
+		return 4, errors.New("Exists : invalid filepath")
+	}
+
+	switch ftype := fi.Mode(); {
+	case ftype.IsDir():
+		return 0, nil
+	case ftype.IsRegular():
+		return 1, nil
+	default:
+		return 2, nil
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// section 이름의 계층은 점(.)으로 구분합니다.
+// [server.web]과 git 형식의 [server "web"]은 같은 section입니다.
+// =======================================
+//
+// [tenant "alpha"]		--> tenant.alpha
+// [tenant.alpha.db]		--> tenant.alpha.db
+// [tenant "beta"]		--> tenant.beta
+//
+// GetChildList("tenant")	--> [alpha beta]
+// GetSubtree("tenant.alpha")	--> {"": {...}, "db": {...}}
+//
+// =======================================
+
+// GetChildList 함수는 parent의 바로 아래 단계 section 이름을 정렬하여 반환합니다.
+// 반환되는 이름은 parent를 제외한 한 단계의 이름이며, parent가 공백일 경우 최상위 section 이름을 반환합니다.
+// parent는 NameMatch 방식에 따라 비교합니다.
+// [a.b.c]만 존재하는 경우에도 GetChildList("a")는 b를 반환합니다.
+func (conf *Configuration) GetChildList(parent string) []string {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	children := map[string]bool{}
+	for name := range conf.sections {
+		relative, ok := relativeSection(conf.fold(), parent, name)
+		if !ok || relative == "" {
+			continue
+		}
+		if dot := strings.Index(relative, "."); dot != -1 {
+			relative = relative[:dot]
+		}
+		children[relative] = true
+	}
+
+	var list []string
+	for child := range children {
+		list = append(list, child)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// GetSubtree 함수는 parent와 그 하위 section의 모든 [key=value]를 반환합니다.
+// map의 key는 parent 기준의 상대 이름이며, parent 자신은 공백입니다.
+// secret value는 Find와 같이 복호화하여 반환합니다.
+func (conf *Configuration) GetSubtree(parent string) map[string]map[string]string {
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	subtree := map[string]map[string]string{}
+	for name, targetsection := range conf.sections {
+		relative, ok := relativeSection(conf.fold(), parent, name)
+		if !ok {
+			continue
+		}
+
+		data := make(map[string]string, len(targetsection.data))
+		for key, value := range targetsection.data {
+			data[key], _ = conf.reveal(value)
+		}
+		subtree[relative] = data
+	}
+	return subtree
+}
+
+// DeleteSubtree 함수는 parent와 그 하위 section을 모두 삭제합니다.
+// 하위 section부터 삭제하며, 삭제할 section이 없을 경우 에러를 반환합니다.
+func (conf *Configuration) DeleteSubtree(parent string) error {
+	if parent == "" {
+		return errors.New("DeleteSubtree : missing section")
+	}
+
+	var names []string
+	for relative := range conf.GetSubtree(parent) {
+		names = append(names, strings.TrimSuffix(parent+"."+relative, "."))
+	}
+	if len(names) == 0 {
+		return errors.New("DeleteSubtree : cannot find section")
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	for _, name := range names {
+		if err := conf.DeleteSection(name); err != nil {
+			return errors.New(fmt.Sprint("DeleteSubtree : ", err))
+		}
+	}
+	return nil
+}
+
+// BindSubtree 함수는 parent의 하위 section들을 map[string]Struct 또는 map[string]*Struct에 저장합니다.
+// map의 key는 GetChildList의 이름이며, struct의 field는 해당 section의 key와 대소문자 구분 없이 대응됩니다.
+// `conf:"name"` 태그로 key 이름을 지정할 수 있으며, `conf:"-"`는 무시합니다.
+// `conf:"name,sensitive"` 태그는 MarkSensitiveSchema에서 사용하는 옵션으로, 값을 저장할 때는 영향이 없습니다.
+// struct 타입의 field는 한 단계 아래 section에 대응되며, section 이름도 대소문자를 구분하지 않습니다.
+// =======================================
+//
+// type Tenant struct { Host string; Port int; Timeout time.Duration `conf:"timeout"`; DB struct{ Name string } }
+//
+// [tenant "alpha"]		--> tenants["alpha"].Host, Port, Timeout
+// [tenant.alpha.db]		--> tenants["alpha"].DB.Name
+//
+// var tenants map[string]Tenant
+// err := conf.BindSubtree("tenant", &tenants)
+//
+// =======================================
+func (conf *Configuration) BindSubtree(parent string, target interface{}) error {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Ptr || pointer.IsNil() || pointer.Elem().Kind() != reflect.Map || pointer.Elem().Type().Key().Kind() != reflect.String {
+		return errors.New("BindSubtree : target must be a pointer to map[string]struct")
+	}
+
+	mapvalue := pointer.Elem()
+	elemtype := mapvalue.Type().Elem()
+	structtype := elemtype
+	if elemtype.Kind() == reflect.Ptr {
+		structtype = elemtype.Elem()
+	}
+	if structtype.Kind() != reflect.Struct {
+		return errors.New("BindSubtree : target must be a pointer to map[string]struct")
+	}
+
+	subtree := conf.GetSubtree(parent)
+	if mapvalue.IsNil() {
+		mapvalue.Set(reflect.MakeMap(mapvalue.Type()))
+	}
+
+	for _, child := range conf.GetChildList(parent) {
+		item := reflect.New(structtype)
+		if err := conf.bindStruct(item.Elem(), subtree, parent, child); err != nil {
+			return errors.New(fmt.Sprint("BindSubtree : ", joinSection(parent, child), " ", err))
+		}
+
+		if elemtype.Kind() == reflect.Ptr {
+			mapvalue.SetMapIndex(reflect.ValueOf(child).Convert(mapvalue.Type().Key()), item)
+		} else {
+			mapvalue.SetMapIndex(reflect.ValueOf(child).Convert(mapvalue.Type().Key()), item.Elem())
+		}
+	}
+	return nil
+}
+
+// relativeSection 함수는 name이 parent 또는 그 하위 section일 경우 parent 기준의 상대 이름을 반환합니다.
+// 각 단계의 이름은 fold에 따라 비교하며, 상대 이름은 name에 기록된 그대로 반환합니다.
+func relativeSection(fold func(string) string, parent, name string) (string, bool) {
+	if parent == "" {
+		return name, true
+	}
+
+	parents := strings.Split(parent, ".")
+	names := strings.Split(name, ".")
+	if len(names) < len(parents) {
+		return "", false
+	}
+	for i := range parents {
+		if !sameName(fold, names[i], parents[i]) {
+			return "", false
+		}
+	}
+	return strings.Join(names[len(parents):], "."), true
+}
+
+// bindStruct 함수는 parent 기준 subtree의 name section을 struct에 저장합니다.
+func (conf *Configuration) bindStruct(target reflect.Value, subtree map[string]map[string]string, parent, name string) error {
+	data := subtree[name]
+
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key, _, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			child := joinSection(name, key)
+			for relative := range subtree {
+				if strings.EqualFold(relative, child) {
+					child = relative
+					break
+				}
+			}
+			if err := conf.bindStruct(target.Field(i), subtree, parent, child); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for option, value := range data {
+			if strings.EqualFold(option, key) {
+				if err := conf.bindValue(target.Field(i), joinSection(parent, name), option, value); err != nil {
+					return errors.New(fmt.Sprint(option, " : ", err))
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// fieldKey 함수는 struct field에 대응되는 key 이름과 sensitive 옵션을 반환합니다.
+// `conf:"-"` 태그가 지정된 field는 false를 반환합니다.
+// =======================================
+//
+// Password string `conf:"password,sensitive"`	--> password, true
+// Host string					--> Host, false
+//
+// =======================================
+func fieldKey(field reflect.StructField) (string, bool, bool) {
+	tag, ok := field.Tag.Lookup("conf")
+	if !ok {
+		return field.Name, false, true
+	}
+	if tag == "-" {
+		return "", false, false
+	}
+
+	key, sensitive := field.Name, false
+	for i, option := range strings.Split(tag, ",") {
+		switch {
+		case i == 0 && option != "":
+			key = option
+		case i != 0 && option == "sensitive":
+			sensitive = true
+		}
+	}
+	return key, sensitive, true
+}
+
+// bindValue 함수는 value를 field의 타입으로 변환하여 저장합니다.
+// []string은 SetListStyle로 지정된 방식으로 구분된 목록입니다.
+// 변환에 실패한 경우 에러 메시지의 민감한 value는 Redacted로 가려집니다.
+func (conf *Configuration) bindValue(field reflect.Value, section, key, value string) error {
+	value = strings.TrimSpace(value)
+	invalid := errors.New(fmt.Sprint("invalid value ", strconv.Quote(conf.redact(section, key, value))))
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		ret, err := parseBool(value)
+		if err != nil {
+			return invalid
+		}
+		field.SetBool(ret)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			ret, err := time.ParseDuration(value)
+			if err != nil {
+				return invalid
+			}
+			field.SetInt(int64(ret))
+			return nil
+		}
+		ret, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		field.SetInt(ret)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ret, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		field.SetUint(ret)
+	case reflect.Float32, reflect.Float64:
+		ret, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		field.SetFloat(ret)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return errors.New(fmt.Sprint("unsupported type ", field.Type()))
+		}
+		items, err := SplitList(value, conf.listStyle())
+		if err != nil {
+			return invalid
+		}
+		list := reflect.MakeSlice(field.Type(), 0, len(items))
+		for _, item := range items {
+			list = reflect.Append(list, reflect.ValueOf(item).Convert(field.Type().Elem()))
+		}
+		field.Set(list)
+	default:
+		return errors.New(fmt.Sprint("unsupported type ", field.Type()))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package conf4g
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubsectionFunction(t *testing.T) {
+
+	/*
+		[tenant "alpha"]
+		host=alpha.internal
+
+		[tenant.alpha.db]
+		name=alpha
+
+		variable.GetChildList("tenant")	--> [alpha beta]
+		variable.Find("tenant.alpha", "host")	--> alpha.internal
+	*/
+
+	Convey("Subsection Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[tenant \"alpha\"]\nhost=alpha.internal\nport=8080\ntimeout=5s\ntags=a, b\n"+
+			"[tenant.alpha.db]\nname=alpha\n"+
+			"[tenant \"beta\"]\nhost=beta.internal\nport=9090\n"+
+			"[remote \"fix \\\"a\\\"\"]\nurl=git\n"+
+			"[server.web]\nport=80\n")
+
+		conf, _ := New(WithPath(path))
+
+		Convey("Subsection Parse", func() {
+			So(conf.Find("tenant.alpha", "host"), ShouldEqual, "alpha.internal")
+			So(conf.Find("tenant.alpha.db", "name"), ShouldEqual, "alpha")
+			So(conf.Find(`remote.fix "a"`, "url"), ShouldEqual, "git")
+
+			So(conf.Write("tenant.beta", "port", "9091"), ShouldBeNil)
+			So(conf.Write("tenant.gamma", "port", "7070"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldContainSubstring, "[tenant \"beta\"]\nhost=beta.internal\nport=9091\n")
+			So(string(data), ShouldContainSubstring, "[remote \"fix \\\"a\\\"\"]\n")
+			So(string(data), ShouldContainSubstring, "[tenant.gamma]\nport=7070\n")
+		})
+
+		Convey("Subsection Child", func() {
+			So(conf.GetChildList("tenant"), ShouldResemble, []string{"alpha", "beta"})
+			So(conf.GetChildList("tenant.alpha"), ShouldResemble, []string{"db"})
+			So(conf.GetChildList(""), ShouldResemble, []string{"remote", "server", "tenant"})
+			So(conf.GetChildList("missing"), ShouldBeEmpty)
+		})
+
+		Convey("Subsection Subtree", func() {
+			So(conf.GetSubtree("tenant.alpha"), ShouldResemble, map[string]map[string]string{
+				"":   {"host": "alpha.internal", "port": "8080", "timeout": "5s", "tags": "a, b"},
+				"db": {"name": "alpha"},
+			})
+
+			So(conf.DeleteSubtree("tenant"), ShouldBeNil)
+			So(conf.GetChildList("tenant"), ShouldBeEmpty)
+			So(conf.Find("server.web", "port"), ShouldEqual, "80")
+			So(conf.DeleteSubtree("tenant"), ShouldNotBeNil)
+		})
+
+		Convey("Subsection Name Match", func() {
+			So(conf.GetChildList("Tenant"), ShouldBeEmpty)
+
+			conf.SetNameMatch(MatchIgnoreCase)
+			So(conf.GetChildList("Tenant"), ShouldResemble, []string{"alpha", "beta"})
+			So(conf.GetChildList("TENANT.Alpha"), ShouldResemble, []string{"db"})
+			So(conf.GetSubtree("Tenant.Alpha")["db"], ShouldResemble, map[string]string{"name": "alpha"})
+
+			So(conf.DeleteSubtree("Tenant"), ShouldBeNil)
+			So(conf.GetChildList("tenant"), ShouldBeEmpty)
+			So(conf.Find("server.web", "port"), ShouldEqual, "80")
+		})
+
+		Convey("Subsection Bind", func() {
+			type Tenant struct {
+				Host    string
+				Port    int
+				Timeout time.Duration `conf:"timeout"`
+				Tags    []string
+				Ignored string `conf:"-"`
+				DB      struct{ Name string }
+			}
+
+			var tenants map[string]Tenant
+			So(conf.BindSubtree("tenant", &tenants), ShouldBeNil)
+			So(len(tenants), ShouldEqual, 2)
+			So(tenants["alpha"].Host, ShouldEqual, "alpha.internal")
+			So(tenants["alpha"].Timeout, ShouldEqual, 5*time.Second)
+			So(tenants["alpha"].Tags, ShouldResemble, []string{"a", "b"})
+			So(tenants["alpha"].DB.Name, ShouldEqual, "alpha")
+			So(tenants["beta"].Port, ShouldEqual, 9090)
+
+			pointers := map[string]*Tenant{}
+			So(conf.BindSubtree("tenant", &pointers), ShouldBeNil)
+			So(pointers["beta"].Host, ShouldEqual, "beta.internal")
+
+			So(conf.BindSubtree("tenant", tenants), ShouldNotBeNil)
+
+			var invalid map[string]struct{ Host int }
+			So(conf.BindSubtree("tenant", &invalid), ShouldNotBeNil)
+		})
+
+		Convey("Subsection Bind Redact", func() {
+			So(conf.Write("tenant.alpha", "password", "s3cret"), ShouldBeNil)
+			So(conf.Write("tenant.alpha", "tags", "\"a, b\", c"), ShouldBeNil)
+
+			var secrets map[string]struct{ Password int }
+			err := conf.BindSubtree("tenant", &secrets)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldNotContainSubstring, "s3cret")
+
+			var tagged map[string]struct{ Tags []string }
+			So(conf.BindSubtree("tenant", &tagged), ShouldBeNil)
+			So(tagged["alpha"].Tags, ShouldResemble, []string{"a, b", "c"})
+		})
+	})
+}
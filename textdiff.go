@@ -0,0 +1,159 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext 는 unified diff의 변경 내용 앞뒤에 출력할 줄 수입니다.
+const diffContext = 3
+
+// lineOp 구조체는 줄 단위 비교 결과의 한 줄입니다.
+// kind는 ' '(동일), '-'(삭제), '+'(추가) 중 하나입니다.
+type lineOp struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff 함수는 두 내용을 줄 단위로 비교하여 unified diff 형식의 문자열을 반환합니다.
+// aname과 bname은 "---", "+++" 헤더에 출력되는 이름이며, 변경 내용 앞뒤로 3줄을 함께 출력합니다.
+// 두 내용이 동일할 경우 공백값을 반환합니다.
+func UnifiedDiff(aname, bname string, a, b []byte) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "--- %s\n+++ %s\n", aname, bname)
+
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// 변경 내용 앞뒤로 diffContext 만큼의 동일한 줄을 포함하는 hunk를 구성합니다.
+		// 동일한 줄이 diffContext*2 이하인 변경 내용은 하나의 hunk로 합칩니다.
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			same := end
+			for same < len(ops) && ops[same].kind == ' ' {
+				same++
+			}
+			if same == len(ops) || same-end > diffContext*2 {
+				break
+			}
+			end = same
+		}
+		to := end + diffContext
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		aline, bline := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				aline++
+			}
+			if op.kind != '-' {
+				bline++
+			}
+		}
+		acount, bcount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				acount++
+			}
+			if op.kind != '-' {
+				bcount++
+			}
+		}
+		if acount == 0 {
+			aline--
+		}
+		if bcount == 0 {
+			bline--
+		}
+
+		fmt.Fprintf(&text, "@@ -%d,%d +%d,%d @@\n", aline, acount, bline, bcount)
+		for _, op := range ops[from:to] {
+			fmt.Fprintf(&text, "%c%s\n", op.kind, op.text)
+		}
+		start = to
+	}
+	return text.String()
+}
+
+// diffLines 함수는 최장 공통 부분 수열(LCS)을 이용하여 두 줄 목록의 차이를 계산합니다.
+func diffLines(a, b []string) []lineOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, lineOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, lineOp{'+', b[j]})
+	}
+	return ops
+}
+
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
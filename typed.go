@@ -0,0 +1,88 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FindInt 함수는 지정된 section과 key의 value를 int로 변환하여 반환합니다.
+// value가 존재하지 않거나 변환할 수 없을 경우 에러를 반환합니다.
+// 민감한 key의 value는 에러 메시지에 포함되지 않습니다.
+func (conf *Configuration) FindInt(section, key string) (int, error) {
+	value, err := conf.ExistValue(section, key)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, perr := strconv.Atoi(strings.TrimSpace(value))
+	if perr != nil {
+		return 0, errors.New(fmt.Sprint("FindInt : invalid value ", strconv.Quote(conf.redact(section, key, value))))
+	}
+	return ret, nil
+}
+
+// FindBool 함수는 지정된 section과 key의 value를 bool로 변환하여 반환합니다.
+// true/false, 1/0 외에 yes/no, on/off를 허용합니다.
+// value가 존재하지 않거나 변환할 수 없을 경우 에러를 반환합니다.
+func (conf *Configuration) FindBool(section, key string) (bool, error) {
+	value, err := conf.ExistValue(section, key)
+	if err != nil {
+		return false, err
+	}
+
+	ret, perr := parseBool(value)
+	if perr != nil {
+		return false, errors.New(fmt.Sprint("FindBool : invalid value ", strconv.Quote(conf.redact(section, key, value))))
+	}
+	return ret, nil
+}
+
+// parseBool 함수는 FindBool의 내부 함수로, yes/no, on/off를 허용하는 strconv.ParseBool입니다.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+// FindFloat 함수는 지정된 section과 key의 value를 float64로 변환하여 반환합니다.
+// value가 존재하지 않거나 변환할 수 없을 경우 에러를 반환합니다.
+func (conf *Configuration) FindFloat(section, key string) (float64, error) {
+	value, err := conf.ExistValue(section, key)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, perr := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if perr != nil {
+		return 0, errors.New(fmt.Sprint("FindFloat : invalid value ", strconv.Quote(conf.redact(section, key, value))))
+	}
+	return ret, nil
+}
+
+// FindDuration 함수는 지정된 section과 key의 value를 time.Duration으로 변환하여 반환합니다.
+// value는 "1h30m"과 같은 time.ParseDuration 형식입니다.
+// value가 존재하지 않거나 변환할 수 없을 경우 에러를 반환합니다.
+func (conf *Configuration) FindDuration(section, key string) (time.Duration, error) {
+	value, err := conf.ExistValue(section, key)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, perr := time.ParseDuration(strings.TrimSpace(value))
+	if perr != nil {
+		return 0, errors.New(fmt.Sprint("FindDuration : invalid value ", strconv.Quote(conf.redact(section, key, value))))
+	}
+	return ret, nil
+}
@@ -0,0 +1,61 @@
+package conf4g
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTypedFunction(t *testing.T) {
+
+	/*
+		configdata :
+
+		[Server]
+		Port=8080
+		Debug=yes
+		Ratio=0.5
+		Timeout=1m30s
+
+		variable.FindInt("Server", "Port")
+
+		--> 8080
+	*/
+
+	Convey("Typed Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[Server]\nPort=8080\nDebug=yes\nRatio=0.5\nTimeout=1m30s\nName=web\n")
+		conf := makeTestConfig(path)
+
+		Convey("Typed Value", func() {
+			port, err := conf.FindInt("Server", "Port")
+			So(err, ShouldBeNil)
+			So(port, ShouldEqual, 8080)
+
+			debug, err := conf.FindBool("Server", "Debug")
+			So(err, ShouldBeNil)
+			So(debug, ShouldBeTrue)
+
+			ratio, err := conf.FindFloat("Server", "Ratio")
+			So(err, ShouldBeNil)
+			So(ratio, ShouldEqual, 0.5)
+
+			timeout, err := conf.FindDuration("Server", "Timeout")
+			So(err, ShouldBeNil)
+			So(timeout, ShouldEqual, 90*time.Second)
+		})
+
+		Convey("Typed Invalid", func() {
+			_, err := conf.FindInt("Server", "Name")
+			So(err, ShouldNotBeNil)
+
+			_, err = conf.FindBool("Server", "Name")
+			So(err, ShouldNotBeNil)
+
+			_, err = conf.FindInt("Server", "Missing")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ErrConflict 에러는 지정된 버전 이후에 config 파일이 변경되었을 때 반환됩니다.
+var ErrConflict = errors.New("conflict : configuration changed since version")
+
+// Version 함수는 현재 config 파일의 버전 토큰을 반환합니다.
+// 버전 토큰은 include와 conf.d 파일을 포함한 모든 파일의 내용 hash와 최종 수정 시각으로 구성됩니다.
+// =======================================
+//
+// {sha256(경로 + 내용) 앞 24자리}-{최종 수정 시각(36진수 nanosecond)}
+//
+// 3f1c9a0d5e7b2c4a6f8e1d3b-lbh2k9x1c0
+//
+// =======================================
+func (conf *Configuration) Version() (string, error) {
+	if err := conf.Read(); err != nil {
+		return "", fmt.Errorf("Version : %w", err)
+	}
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	return conf.version()
+}
+
+// WriteIf 함수는 config 파일이 version 이후에 변경되지 않았을 경우에만 value를 기록합니다.
+// 변경되었을 경우 아무것도 기록하지 않고 ErrConflict를 반환합니다.
+// 프로세스 내부의 동기 처리는 Write와 같이 mutex를 사용합니다.
+func (conf *Configuration) WriteIf(version, section, key, value string) error {
+	conf.Read()
+	conf.mu.Lock()
+
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+	}()
+
+	if err := conf.verify(version); err != nil {
+		return fmt.Errorf("WriteIf : %w", err)
+	}
+	return conf.write(section, key, value)
+}
+
+// Transaction 구조체는 하나의 버전을 기준으로 모아서 기록할 변경 목록입니다.
+// Commit 시 config 파일이 시작 버전 이후에 변경되었을 경우 ErrConflict를 반환합니다.
+type Transaction struct {
+	conf    *Configuration
+	version string
+	actions []mutation
+}
+
+// Begin 함수는 현재 버전을 기준으로 하는 Transaction을 시작합니다.
+func (conf *Configuration) Begin() (*Transaction, error) {
+	version, err := conf.Version()
+	if err != nil {
+		return nil, errors.New(fmt.Sprint("Begin : ", err))
+	}
+	return &Transaction{conf: conf, version: version}, nil
+}
+
+// Version 함수는 Transaction의 기준 버전을 반환합니다.
+func (tx *Transaction) Version() string { return tx.version }
+
+// Set 함수는 value의 기록을 Transaction에 추가합니다.
+func (tx *Transaction) Set(section, key, value string) {
+	tx.actions = append(tx.actions, mutation{section: section, key: key, value: value})
+}
+
+// Delete 함수는 value의 삭제를 Transaction에 추가합니다.
+func (tx *Transaction) Delete(section, key string) {
+	tx.actions = append(tx.actions, mutation{section: section, key: key, delete: true})
+}
+
+// Discard 함수는 Transaction에 추가된 변경 목록을 모두 취소합니다.
+func (tx *Transaction) Discard() { tx.actions = nil }
+
+// Commit 함수는 Transaction의 변경 목록을 순서대로 적용하여 파일별로 한번씩 기록합니다.
+// 변경 중 하나라도 유효하지 않을 경우 아무것도 기록하지 않고 에러를 반환합니다.
+// 기준 버전 이후에 config 파일이 변경되었을 경우 아무것도 기록하지 않고 ErrConflict를 반환합니다.
+// 기록 후 기준 버전은 새로운 버전으로 갱신되므로 Transaction을 계속 사용할 수 있습니다.
+// ErrConflict가 반환된 경우 기준 버전은 갱신되지 않으므로, Begin으로 새로운 Transaction을 시작해야 합니다.
+func (tx *Transaction) Commit() error {
+	conf := tx.conf
+
+	conf.Read()
+	conf.mu.Lock()
+
+	committed := false
+	defer func() {
+		conf.mu.Unlock()
+		conf.Read()
+		// 충돌 또는 실패한 경우 기준 버전을 유지하므로, 다시 Commit해도 ErrConflict를 반환합니다.
+		if !committed {
+			return
+		}
+		if version, err := conf.Version(); err == nil {
+			tx.version = version
+		}
+	}()
+
+	if err := conf.verify(tx.version); err != nil {
+		return fmt.Errorf("Commit : %w", err)
+	}
+	if err := conf.apply(tx.actions); err != nil {
+		return errors.New(fmt.Sprint("Commit : ", err))
+	}
+	tx.actions, committed = nil, true
+	return nil
+}
+
+// verify 함수는 현재 버전이 version과 같은지 확인합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) verify(version string) error {
+	current, err := conf.version()
+	if err != nil {
+		return err
+	}
+	if current != version {
+		return fmt.Errorf("%w : expected %s, current %s", ErrConflict, version, current)
+	}
+	return nil
+}
+
+// version 함수는 병합된 모든 파일의 버전 토큰을 계산합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) version() (string, error) {
+	sources := conf.sources
+	if len(sources) == 0 {
+		sources = []string{conf.confpath}
+	}
+
+	hash := sha256.New()
+	var modified int64
+
+	for _, path := range sources {
+		fmt.Fprintf(hash, "%s\x00", path)
+
+		data, err := conf.storage().ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		hash.Write(data)
+		hash.Write([]byte{0})
+
+		if info, serr := conf.storage().Stat(path); serr == nil && info.ModTime().UnixNano() > modified {
+			modified = info.ModTime().UnixNano()
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:24] + "-" + strconv.FormatInt(modified, 36), nil
+}
@@ -0,0 +1,92 @@
+package conf4g
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVersionFunction(t *testing.T) {
+
+	/*
+		version, _ := variable.Version()
+
+		(다른 프로세스가 config 파일을 변경)
+
+		variable.WriteIf(version, "Section001", "Key001", "Value002")
+
+		--> ErrConflict
+	*/
+
+	Convey("Version Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[Section001]\nKey001=Value001\n")
+
+		conf := makeTestConfig(path)
+
+		Convey("Version Token", func() {
+			version, err := conf.Version()
+			So(err, ShouldBeNil)
+			So(version, ShouldNotBeEmpty)
+
+			again, _ := conf.Version()
+			So(again, ShouldEqual, version)
+
+			conf.Write("Section001", "Key001", "Value002")
+			changed, _ := conf.Version()
+			So(changed, ShouldNotEqual, version)
+		})
+
+		Convey("Version WriteIf", func() {
+			version, _ := conf.Version()
+			So(conf.WriteIf(version, "Section001", "Key001", "Value002"), ShouldBeNil)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value002")
+
+			err := conf.WriteIf(version, "Section001", "Key001", "Value003")
+			So(errors.Is(err, ErrConflict), ShouldBeTrue)
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value002")
+		})
+
+		Convey("Version Transaction", func() {
+			tx, err := conf.Begin()
+			So(err, ShouldBeNil)
+
+			tx.Set("Section001", "Key002", "Value002")
+			tx.Delete("Section001", "Key001")
+			So(tx.Commit(), ShouldBeNil)
+
+			So(conf.Find("Section001", "Key002"), ShouldEqual, "Value002")
+			So(conf.Find("Section001", "Key001"), ShouldBeEmpty)
+
+			current, _ := conf.Version()
+			So(tx.Version(), ShouldEqual, current)
+
+			writeTestFile(path, "[Section001]\nKey002=External\n")
+
+			tx.Set("Section001", "Key002", "Value003")
+			err = tx.Commit()
+			So(errors.Is(err, ErrConflict), ShouldBeTrue)
+			So(conf.Find("Section001", "Key002"), ShouldEqual, "External")
+
+			err = tx.Commit()
+			So(errors.Is(err, ErrConflict), ShouldBeTrue)
+			So(conf.Find("Section001", "Key002"), ShouldEqual, "External")
+		})
+
+		Convey("Version Transaction Invalid", func() {
+			before, _ := os.ReadFile(path)
+			tx, _ := conf.Begin()
+
+			tx.Set("Section001", "Key001", "Value002")
+			tx.Set("Section001", "Key002", "")
+			So(tx.Commit(), ShouldNotBeNil)
+
+			after, _ := os.ReadFile(path)
+			So(string(after), ShouldEqual, string(before))
+			So(conf.Find("Section001", "Key001"), ShouldEqual, "Value001")
+		})
+	})
+}
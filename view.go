@@ -0,0 +1,244 @@
+// Copyright © 2022 Park Seong Ho <sh26@kakao.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package conf4g
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// View 구조체는 하나의 section 또는 같은 접두어를 가진 section들로 범위가 제한된 Configuration입니다.
+// View는 이름만 보관하므로 config 파일이 다시 읽혀도 항상 최신 내용을 반환합니다.
+// =======================================
+//
+// Section("database")	: [database] section의 key를 사용합니다.
+//
+// Prefix("cache")		: [cache]와 [cache.*] section을 사용합니다.
+// key는 마지막 점(.)을 기준으로 section과 key로 나뉩니다.
+//
+// host		--> [cache] host
+// redis.host	--> [cache.redis] host
+//
+// =======================================
+type View struct {
+	conf   *Configuration
+	name   string
+	prefix bool
+}
+
+// watcher 구조체는 View에 등록된 하나의 변경 구독입니다.
+type watcher struct {
+	id   int
+	view *View
+	fn   func(Changes)
+}
+
+// Section 함수는 지정된 section으로 범위가 제한된 View를 반환합니다.
+// section이 존재하지 않아도 View를 반환하며, Set으로 section을 생성할 수 있습니다.
+func (conf *Configuration) Section(name string) *View {
+	return &View{conf: conf, name: name}
+}
+
+// Prefix 함수는 지정된 접두어로 시작하는 section들로 범위가 제한된 View를 반환합니다.
+// 접두어 끝의 점(.)은 생략할 수 있습니다. (예: "cache."와 "cache"는 같습니다.)
+func (conf *Configuration) Prefix(prefix string) *View {
+	return &View{conf: conf, name: strings.TrimSuffix(prefix, "."), prefix: true}
+}
+
+// Name 함수는 View의 section 이름 또는 접두어를 반환합니다.
+func (view *View) Name() string { return view.name }
+
+// Get 함수는 View의 key에 대한 value를 반환합니다. Find와 같습니다.
+func (view *View) Get(key string) string {
+	section, name := view.resolve(key)
+	return view.conf.Find(section, name)
+}
+
+// Lookup 함수는 View의 key에 대한 value를 반환합니다. ExistValue와 같습니다.
+func (view *View) Lookup(key string) (string, error) {
+	section, name := view.resolve(key)
+	return view.conf.ExistValue(section, name)
+}
+
+// Set 함수는 View의 key에 value를 기록합니다. Write와 같습니다.
+func (view *View) Set(key, value string) error {
+	section, name := view.resolve(key)
+	return view.conf.Write(section, name, value)
+}
+
+// Delete 함수는 View의 key를 삭제합니다. DeleteValue와 같습니다.
+func (view *View) Delete(key string) error {
+	section, name := view.resolve(key)
+	return view.conf.DeleteValue(section, name)
+}
+
+// Keys 함수는 View의 모든 key를 정렬하여 반환합니다.
+// Prefix View의 key는 접두어 이후의 section 이름을 포함합니다. (예: redis.host)
+func (view *View) Keys() []string {
+	view.conf.Read()
+
+	view.conf.mu.Lock()
+	defer view.conf.mu.Unlock()
+
+	var keys []string
+	for name, targetsection := range view.conf.sections {
+		if !view.contains(name) {
+			continue
+		}
+
+		rest, _ := view.relative(name)
+		for _, key := range keyNames(targetsection) {
+			if view.prefix && rest != "" {
+				key = rest + "." + key
+			}
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Int 함수는 View의 key에 대한 value를 int로 반환합니다. FindInt와 같습니다.
+func (view *View) Int(key string) (int, error) {
+	section, name := view.resolve(key)
+	return view.conf.FindInt(section, name)
+}
+
+// Bool 함수는 View의 key에 대한 value를 bool로 반환합니다. FindBool과 같습니다.
+func (view *View) Bool(key string) (bool, error) {
+	section, name := view.resolve(key)
+	return view.conf.FindBool(section, name)
+}
+
+// Float 함수는 View의 key에 대한 value를 float64로 반환합니다. FindFloat와 같습니다.
+func (view *View) Float(key string) (float64, error) {
+	section, name := view.resolve(key)
+	return view.conf.FindFloat(section, name)
+}
+
+// Duration 함수는 View의 key에 대한 value를 time.Duration으로 반환합니다. FindDuration과 같습니다.
+func (view *View) Duration(key string) (time.Duration, error) {
+	section, name := view.resolve(key)
+	return view.conf.FindDuration(section, name)
+}
+
+// OnChange 함수는 View 범위의 내용이 변경될 때 호출할 함수를 등록하고, 등록을 취소하는 함수를 반환합니다.
+// 변경은 Read로 config 파일을 다시 읽을 때 이전 내용과 비교하여 확인하므로,
+// 이 프로세스의 Write뿐 아니라 다른 프로세스가 변경한 내용도 다음 Read 시점에 전달됩니다.
+// fn은 mutex를 사용하지 않는 상태로 호출되므로 Configuration의 함수를 사용할 수 있습니다.
+// =======================================
+//
+// cancel := conf.Section("database").OnChange(func(changes conf4g.Changes) { reconnect() })
+// defer cancel()
+//
+// =======================================
+func (view *View) OnChange(fn func(Changes)) (cancel func()) {
+	conf := view.conf
+	conf.Read()
+
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+
+	conf.watchid++
+	id := conf.watchid
+	conf.watchers = append(conf.watchers, watcher{id: id, view: view, fn: fn})
+	if conf.observed == nil {
+		conf.observed = conf.sections
+	}
+
+	return func() {
+		conf.mu.Lock()
+		defer conf.mu.Unlock()
+
+		for i, target := range conf.watchers {
+			if target.id == id {
+				conf.watchers = append(conf.watchers[:i:i], conf.watchers[i+1:]...)
+				break
+			}
+		}
+		if len(conf.watchers) == 0 {
+			conf.observed = nil
+		}
+	}
+}
+
+// resolve 함수는 View의 key를 section과 key로 변환합니다.
+func (view *View) resolve(key string) (string, string) {
+	if !view.prefix {
+		return view.name, key
+	}
+
+	dot := strings.LastIndex(key, ".")
+	if dot < 0 {
+		return view.name, key
+	}
+	if view.name == "" {
+		return key[:dot], key[dot+1:]
+	}
+	return view.name + "." + key[:dot], key[dot+1:]
+}
+
+// contains 함수는 section이 View의 범위에 포함되는지 확인합니다.
+func (view *View) contains(section string) bool {
+	if !view.prefix {
+		return sameName(view.conf.fold(), section, view.name)
+	}
+	_, ok := view.relative(section)
+	return ok
+}
+
+// relative 함수는 section이 Prefix View의 범위에 포함될 경우 접두어 이후의 section 이름을 반환합니다.
+// 접두어는 SetNameMatch로 지정된 비교 방식에 따라 점(.)으로 구분된 section 이름의 앞부분과 비교됩니다.
+func (view *View) relative(section string) (string, bool) {
+	if view.name == "" {
+		return section, true
+	}
+
+	segments := strings.Count(view.name, ".") + 1
+	parts := strings.SplitN(section, ".", segments+1)
+	if len(parts) < segments || !sameName(view.conf.fold(), strings.Join(parts[:segments], "."), view.name) {
+		return "", false
+	}
+	if len(parts) == segments {
+		return "", true
+	}
+	return parts[segments], true
+}
+
+// observe 함수는 변경 구독이 있을 경우 마지막으로 알린 내용과 현재 내용의 차이를 반환합니다.
+// 호출하는 함수에서 mutex의 Lock 함수를 사용해야 합니다.
+func (conf *Configuration) observe() Changes {
+	if len(conf.watchers) == 0 {
+		return nil
+	}
+
+	previous := conf.observed
+	conf.observed = conf.sections
+	if previous == nil {
+		return nil
+	}
+	return diffSections(previous, conf.sections, conf.redact, conf.redact)
+}
+
+// notify 함수는 각 변경 구독에 View 범위에 포함되는 변경 내용만 전달합니다.
+func notify(watchers []watcher, changes Changes) {
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, target := range watchers {
+		var scoped Changes
+		for _, change := range changes {
+			if target.view.contains(change.Section) {
+				scoped = append(scoped, change)
+			}
+		}
+		if len(scoped) != 0 {
+			target.fn(scoped)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package conf4g
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestViewFunction(t *testing.T) {
+
+	/*
+		[database]
+		host=localhost
+		port=5432
+
+		[cache.redis]
+		host=127.0.0.1
+
+		variable.Section("database").Get("port")	--> 5432
+		variable.Prefix("cache").Get("redis.host")	--> 127.0.0.1
+	*/
+
+	Convey("View Function", t, func() {
+		path := filepath.Join(t.TempDir(), "app.ini")
+		writeTestFile(path, "[database]\nhost=localhost\nport=5432\ntimeout=5s\n[cache]\nenabled=yes\n[cache.redis]\nhost=127.0.0.1\n[cachex]\nhost=other\n")
+
+		conf, _ := New(WithPath(path))
+
+		Convey("View Section", func() {
+			database := conf.Section("database")
+			So(database.Name(), ShouldEqual, "database")
+			So(database.Get("host"), ShouldEqual, "localhost")
+			So(database.Keys(), ShouldResemble, []string{"host", "port", "timeout"})
+
+			port, err := database.Int("port")
+			So(err, ShouldBeNil)
+			So(port, ShouldEqual, 5432)
+
+			timeout, _ := database.Duration("timeout")
+			So(timeout, ShouldEqual, 5*time.Second)
+
+			So(database.Set("user", "admin"), ShouldBeNil)
+			So(conf.Find("database", "user"), ShouldEqual, "admin")
+
+			So(database.Delete("user"), ShouldBeNil)
+			_, lerr := database.Lookup("user")
+			So(lerr, ShouldNotBeNil)
+		})
+
+		Convey("View Prefix", func() {
+			cache := conf.Prefix("cache.")
+			So(cache.Name(), ShouldEqual, "cache")
+			So(cache.Keys(), ShouldResemble, []string{"enabled", "redis.host"})
+			So(cache.Get("redis.host"), ShouldEqual, "127.0.0.1")
+
+			enabled, _ := cache.Bool("enabled")
+			So(enabled, ShouldBeTrue)
+
+			So(cache.Set("memory.size", "64"), ShouldBeNil)
+			So(conf.Find("cache.memory", "size"), ShouldEqual, "64")
+			So(cache.Keys(), ShouldResemble, []string{"enabled", "memory.size", "redis.host"})
+		})
+
+		Convey("View Live", func() {
+			database := conf.Section("database")
+			So(database.Get("host"), ShouldEqual, "localhost")
+
+			writeTestFile(path, "[database]\nhost=db.internal\n")
+			So(database.Get("host"), ShouldEqual, "db.internal")
+			So(database.Keys(), ShouldResemble, []string{"host"})
+		})
+
+		Convey("View OnChange", func() {
+			var received []Changes
+			cancel := conf.Section("database").OnChange(func(changes Changes) {
+				received = append(received, changes)
+			})
+
+			So(conf.Write("cache.redis", "host", "10.0.0.1"), ShouldBeNil)
+			So(received, ShouldBeEmpty)
+
+			So(conf.Write("database", "port", "6543"), ShouldBeNil)
+			So(received, ShouldResemble, []Changes{{{Section: "database", Key: "port", Kind: ChangeChanged, OldValue: "5432", NewValue: "6543"}}})
+
+			writeTestFile(path, "[database]\nhost=localhost\nport=6543\ntimeout=5s\npassword=p@ss\n")
+			conf.Read()
+			So(len(received), ShouldEqual, 2)
+			So(received[1], ShouldResemble, Changes{{Section: "database", Key: "password", Kind: ChangeAdded, NewValue: Redacted}})
+
+			cancel()
+			So(conf.Write("database", "port", "7654"), ShouldBeNil)
+			So(len(received), ShouldEqual, 2)
+		})
+	})
+}